@@ -9,6 +9,7 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // embed the tz database, the runtime image has none (used for per-user timezones)
 
 	"coffee-tracker-backend/internal/server"
 )
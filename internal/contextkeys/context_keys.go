@@ -7,4 +7,5 @@ type ContextKey string
 const (
 	UserIDKey      ContextKey = "userID"
 	CurrentUserKey ContextKey = "currentUser"
+	ClientIPKey    ContextKey = "clientIP"
 )
\ No newline at end of file
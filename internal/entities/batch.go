@@ -0,0 +1,34 @@
+// file: internal/entities/batch.go
+package entities
+
+import "github.com/google/uuid"
+
+// BatchOp is the kind of a batch operation on coffee entries
+type BatchOp string
+
+const (
+	BatchCreate BatchOp = "create"
+	BatchUpdate BatchOp = "update"
+	BatchDelete BatchOp = "delete"
+)
+
+// BatchItemStatus is the outcome of one batch operation
+type BatchItemStatus string
+
+const (
+	BatchItemCreated    BatchItemStatus = "created"
+	BatchItemUpdated    BatchItemStatus = "updated"
+	BatchItemDeleted    BatchItemStatus = "deleted"
+	BatchItemFailed     BatchItemStatus = "failed"
+	BatchItemNotApplied BatchItemStatus = "not_applied" // valid, but the batch was rolled back
+)
+
+// BatchItemResult reports the outcome of the operation at Index
+type BatchItemResult struct {
+	Index  int             `json:"index"`
+	Op     BatchOp         `json:"op"`
+	ID     uuid.UUID       `json:"id"`
+	Status BatchItemStatus `json:"status"`
+	Entry  *CoffeeEntry    `json:"entry,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
@@ -0,0 +1,75 @@
+// file: internal/entities/caffeine.go
+package entities
+
+import (
+	"math"
+	"time"
+)
+
+// MaxCaffeinePerEntryMg caps manual caffeine overrides to catch typos (e.g. 8000 instead of 80).
+const MaxCaffeinePerEntryMg = 1000
+
+// CaffeineModel estimates the caffeine content of an entry from the
+// coffee_types / coffee_sizes lookups.
+type CaffeineModel struct {
+	TypeMg      map[int]int     // caffeine (mg) of a standard serving, per coffee type
+	SizeFactors map[int]float64 // multiplier applied to the standard serving, per size
+}
+
+// Estimate returns the estimated caffeine (mg) for a type/size pair.
+// Returns nil when the type is unknown, since there is nothing to base the estimate on.
+func (m *CaffeineModel) Estimate(coffeeTypeID, sizeID *int) *int {
+	if m == nil || coffeeTypeID == nil {
+		return nil
+	}
+	base, ok := m.TypeMg[*coffeeTypeID]
+	if !ok {
+		return nil
+	}
+
+	factor := 1.0
+	if sizeID != nil {
+		if f, ok := m.SizeFactors[*sizeID]; ok {
+			factor = f
+		}
+	}
+
+	mg := int(math.Round(float64(base) * factor))
+	return &mg
+}
+
+// DailyCaffeine is the caffeine intake of a single (user-local) day.
+type DailyCaffeine struct {
+	Date          string `json:"date"`
+	TotalCaffeine int    `json:"total_caffeine_mg"`
+	Entries       int    `json:"entries"`
+}
+
+// CaffeineIntake is a single caffeine dose at a point in time.
+type CaffeineIntake struct {
+	Timestamp  time.Time
+	CaffeineMg int
+}
+
+// CaffeineRemaining models first-order elimination: the mg of a dose left in the body
+// after elapsed time, given the elimination half-life. Doses in the future contribute nothing.
+func CaffeineRemaining(doseMg int, elapsed, halfLife time.Duration) float64 {
+	if elapsed < 0 || halfLife <= 0 {
+		return 0
+	}
+	return float64(doseMg) * math.Pow(0.5, elapsed.Hours()/halfLife.Hours())
+}
+
+// CaffeinePoint is the estimated caffeine level at a point in time.
+type CaffeinePoint struct {
+	Time       time.Time `json:"time"`
+	CaffeineMg float64   `json:"caffeine_mg"`
+}
+
+// CaffeineCurve is the estimated caffeine level around a reference time.
+type CaffeineCurve struct {
+	At              time.Time       `json:"at"`
+	HalfLifeMinutes int             `json:"half_life_minutes"`
+	CurrentMg       float64         `json:"current_mg"`
+	Points          []CaffeinePoint `json:"points"`
+}
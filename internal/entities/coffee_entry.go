@@ -8,27 +8,77 @@ import (
 )
 
 type CoffeeEntry struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	UserID      uuid.UUID `json:"user_id" db:"user_id"`
-	CoffeeTypeID *int     `json:"type" db:"coffee_type_id"`     
-	SizeID      *int  	  `json:"size" db:"size_id"`
-	//Caffeine    *int     `json:"caffeine_mg" db:"caffeine_mg"`
-	Notes       *string    `json:"notes" db:"notes"`
-	//Price       float64  `json:"price" db:"price"`
-	//Rating      int      `json:"rating" db:"rating"` // 1-5 scale
-	Latitude  	*float64   `json:"latitude" db:"latitude"`
-    Longitude 	*float64   `json:"longitude" db:"longitude"`
-	Timestamp   time.Time  `json:"timestamp" db:"created_at"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	ID           uuid.UUID  `json:"id" db:"id"`
+	UserID       uuid.UUID  `json:"user_id" db:"user_id"`
+	CoffeeTypeID *int       `json:"type" db:"coffee_type_id"`
+	SizeID       *int       `json:"size" db:"size_id"`
+	Caffeine     *int       `json:"caffeine_mg" db:"caffeine_mg"`
+	Notes        *string    `json:"notes" db:"notes"`
+	Price        *float64   `json:"price" db:"price"`
+	Currency     *string    `json:"currency" db:"currency"` // ISO 4217, set together with Price
+	Rating       *int       `json:"rating" db:"rating"`     // 1-5 scale
+	Latitude     *float64   `json:"latitude" db:"latitude"`
+	Longitude    *float64   `json:"longitude" db:"longitude"`
+	Timestamp    time.Time  `json:"timestamp" db:"created_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty" db:"deleted_at"` // set while the entry is in the trash
+	// Change feed positions (see migrations/009_entry_sync.sql), not exposed to clients
+	Version        int64 `json:"-" db:"version"`
+	CreatedVersion int64 `json:"-" db:"created_version"`
 }
 
 type CoffeeStats struct {
-	TotalEntries     int     `json:"total_entries"`
-	//TotalCaffeine    int     `json:"total_caffeine_mg"`
-	//AverageRating    float64 `json:"average_rating"`
-	//FavoriteCoffee   string  `json:"favorite_coffee"`
-	//TotalSpent       float64 `json:"total_spent"`
-	EntriesThisWeek  int     `json:"entries_this_week"`
-	EntriesThisMonth int     `json:"entries_this_month"`
+	TotalEntries     int                `json:"total_entries"`
+	TotalCaffeine    int                `json:"total_caffeine_mg"`
+	AverageRating    *float64           `json:"average_rating"` // nil when no entry is rated
+	TotalSpent       []CurrencySpending `json:"total_spent"`    // one total per currency
+	EntriesThisWeek  int                `json:"entries_this_week"`
+	EntriesThisMonth int                `json:"entries_this_month"`
+	FavoriteCoffee   *BreakdownItem     `json:"favorite_coffee"`
+	FavoriteSize     *BreakdownItem     `json:"favorite_size"`
+	TypeBreakdown    []BreakdownItem    `json:"type_breakdown"`
+	SizeBreakdown    []BreakdownItem    `json:"size_breakdown"`
+	RatingByType     []RatingItem       `json:"rating_by_type"`
+	RatingByLocation []LocationRating   `json:"rating_by_location"`
+}
+
+// BreakdownItem is the number and share of entries for one coffee type or size
+type BreakdownItem struct {
+	ID    *int    `json:"id"` // nil groups entries logged without a type/size
+	Name  string  `json:"name"`
+	Count int     `json:"count"`
+	Share float64 `json:"share"` // fraction of all entries, 0..1
+}
+
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+// LocationPrecision is the number of decimals coordinates are rounded to when grouping
+// ratings by place (3 decimals is roughly a 100m cell).
+const LocationPrecision = 3
+
+// RatingItem is the average rating of the rated entries of one coffee type
+type RatingItem struct {
+	ID      *int    `json:"id"` // nil groups rated entries logged without a type
+	Name    string  `json:"name"`
+	Average float64 `json:"average"`
+	Count   int     `json:"count"` // rated entries
+}
+
+// LocationRating is the average rating of the rated entries logged around one place
+type LocationRating struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Average   float64 `json:"average"`
+	Count     int     `json:"count"` // rated entries
+}
+
+// EntrySearchResult is a coffee entry matching a notes search
+type EntrySearchResult struct {
+	Entry   *CoffeeEntry `json:"entry"`
+	Rank    float64      `json:"rank"`
+	Snippet string       `json:"snippet"` // HTML-escaped notes excerpt with matches wrapped in <mark></mark>
 }
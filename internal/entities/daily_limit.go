@@ -0,0 +1,21 @@
+// file: internal/entities/daily_limit.go
+package entities
+
+// DailyLimitStatus compares a user's local day against their daily cup/caffeine limits.
+type DailyLimitStatus struct {
+	Date              string `json:"date"`
+	CupsToday         int    `json:"cups_today"`
+	CaffeineTodayMg   int    `json:"caffeine_today_mg"`
+	CupLimit          *int   `json:"cup_limit,omitempty"`
+	CaffeineLimitMg   *int   `json:"caffeine_limit_mg,omitempty"`
+	OverCupLimit      bool   `json:"over_cup_limit"`
+	OverCaffeineLimit bool   `json:"over_caffeine_limit"`
+	// Crossed* are true only for the entry that took the day over the limit
+	CrossedCupLimit      bool `json:"crossed_cup_limit"`
+	CrossedCaffeineLimit bool `json:"crossed_caffeine_limit"`
+}
+
+// HasLimits reports whether the user has any daily limit configured.
+func (s UserSettings) HasLimits() bool {
+	return s.DailyCupLimit != nil || s.DailyCaffeineLimitMg != nil
+}
@@ -0,0 +1,38 @@
+// file: internal/entities/data_export.go
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataExportStatus is the state of an account data export
+type DataExportStatus string
+
+const (
+	DataExportPending DataExportStatus = "pending"
+	DataExportReady   DataExportStatus = "ready"
+	DataExportFailed  DataExportStatus = "failed"
+)
+
+// DataExport is an archive of everything stored about a user, built in the background
+type DataExport struct {
+	ID          uuid.UUID        `json:"id"`
+	UserID      uuid.UUID        `json:"-"`
+	Status      DataExportStatus `json:"status"`
+	ObjectPath  string           `json:"-"` // storage path of the archive, set when ready
+	Error       string           `json:"error,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	// Temporary download link, generated on every read of a ready export
+	DownloadURL    string     `json:"download_url,omitempty"`
+	DownloadExpiry *time.Time `json:"download_expires_at,omitempty"`
+}
+
+// DeviceSession is a device signed in to the account (one refresh token per device)
+type DeviceSession struct {
+	DeviceID  uuid.UUID `json:"device_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	UpdatedAt time.Time `json:"updated_at"` // last sign-in or token refresh
+}
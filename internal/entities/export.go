@@ -0,0 +1,40 @@
+// file: internal/entities/export.go
+package entities
+
+import "time"
+
+// ExportedEntry is a coffee entry with its type and size names localized for an export
+type ExportedEntry struct {
+	*CoffeeEntry
+	TypeName string `json:"type_name"` // empty when the entry has no (known) type
+	SizeName string `json:"size_name"` // empty when the entry has no (known) size
+}
+
+// ExportedSettings is the settings.json document of a data export
+type ExportedSettings struct {
+	BiometricEnabled        bool      `json:"biometric_enabled"`
+	DarkMode                bool      `json:"dark_mode"`
+	NotificationsEnabled    bool      `json:"notifications_enabled"`
+	CaffeineHalfLifeMinutes int       `json:"caffeine_half_life_minutes"`
+	DailyCaffeineLimitMg    *int      `json:"daily_caffeine_limit_mg"`
+	DailyCupLimit           *int      `json:"daily_cup_limit"`
+	CreatedAt               time.Time `json:"created_at"`
+	UpdatedAt               time.Time `json:"updated_at"`
+}
+
+// NewExportedSettings returns nil for a user without settings
+func NewExportedSettings(s *UserSettings) *ExportedSettings {
+	if s == nil {
+		return nil
+	}
+	return &ExportedSettings{
+		BiometricEnabled:        s.BiometricEnabled,
+		DarkMode:                s.DarkMode,
+		NotificationsEnabled:    s.NotificationsEnabled,
+		CaffeineHalfLifeMinutes: s.CaffeineHalfLifeMinutes,
+		DailyCaffeineLimitMg:    s.DailyCaffeineLimitMg,
+		DailyCupLimit:           s.DailyCupLimit,
+		CreatedAt:               s.CreatedAt,
+		UpdatedAt:               s.UpdatedAt,
+	}
+}
@@ -0,0 +1,81 @@
+// file: internal/entities/goal.go
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GoalType identifies the habit a goal tracks
+type GoalType string
+
+const (
+	// GoalMaxCupsPerDay: a day succeeds when at most Target entries were logged
+	GoalMaxCupsPerDay GoalType = "max_cups_per_day"
+	// GoalCaffeineFreeDaysPerWeek: a week (Monday-Sunday) succeeds when at least Target days had no caffeinated entry
+	GoalCaffeineFreeDaysPerWeek GoalType = "caffeine_free_days_per_week"
+)
+
+const MaxGoalCupsPerDay = 50
+
+func (t GoalType) IsValid() bool {
+	switch t {
+	case GoalMaxCupsPerDay, GoalCaffeineFreeDaysPerWeek:
+		return true
+	}
+	return false
+}
+
+// IsValidTarget checks the target against the goal type (0 cups/day means "no coffee at all")
+func (t GoalType) IsValidTarget(target int) bool {
+	switch t {
+	case GoalMaxCupsPerDay:
+		return target >= 0 && target <= MaxGoalCupsPerDay
+	case GoalCaffeineFreeDaysPerWeek:
+		return target >= 1 && target <= 7
+	}
+	return false
+}
+
+// StreakUnit is the period a streak is counted in ("day" or "week")
+func (t GoalType) StreakUnit() StatsBucket {
+	if t == GoalCaffeineFreeDaysPerWeek {
+		return BucketWeek
+	}
+	return BucketDay
+}
+
+type Goal struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Type      GoalType  `json:"type" db:"goal_type"`
+	Target    int       `json:"target" db:"target"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DailyCount holds the entries of one local day; decaf entries (0 mg) are not caffeinated,
+// entries with unknown caffeine are.
+type DailyCount struct {
+	Date               string
+	Entries            int
+	CaffeinatedEntries int
+}
+
+// GoalPeriodStatus describes the current day (or week, for weekly goals) so far
+type GoalPeriodStatus struct {
+	Date             string `json:"date"`
+	PeriodStart      string `json:"period_start"`
+	CupsToday        int    `json:"cups_today"`
+	CaffeineFreeDays *int   `json:"caffeine_free_days,omitempty"` // weekly goals only, today included if still caffeine-free
+	Met              bool   `json:"met"`
+}
+
+type GoalProgress struct {
+	Goal          *Goal            `json:"goal"`
+	StreakUnit    StatsBucket      `json:"streak_unit"`
+	CurrentStreak int              `json:"current_streak"`
+	LongestStreak int              `json:"longest_streak"`
+	Current       GoalPeriodStatus `json:"current"`
+}
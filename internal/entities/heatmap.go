@@ -0,0 +1,30 @@
+// file: internal/entities/heatmap.go
+package entities
+
+// HeatmapDays labels the heatmap rows, ISO order (Monday first)
+var HeatmapDays = [7]string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+// HeatmapCell is the entry count for one ISO weekday (1 = Monday) and hour of day (0-23)
+type HeatmapCell struct {
+	ISOWeekday int
+	Hour       int
+	Entries    int
+}
+
+// HeatmapPeak is the busiest day/hour slot; on a tie, the earliest in the week
+type HeatmapPeak struct {
+	Day     string `json:"day"`
+	Hour    int    `json:"hour"`
+	Entries int    `json:"entries"`
+}
+
+// ConsumptionHeatmap is a 7x24 matrix of entry counts: Matrix[day][hour], Monday first.
+type ConsumptionHeatmap struct {
+	From     string       `json:"from"`
+	To       string       `json:"to"`
+	Timezone string       `json:"tz"`
+	Days     [7]string    `json:"days"`
+	Matrix   [7][24]int   `json:"matrix"`
+	Total    int          `json:"total_entries"`
+	Peak     *HeatmapPeak `json:"peak,omitempty"`
+}
@@ -0,0 +1,25 @@
+// file: internal/entities/idempotency.go
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyRecord is a stored Idempotency-Key and, once the request finished, its response.
+type IdempotencyRecord struct {
+	UserID       uuid.UUID
+	Key          string
+	RequestHash  string // hex SHA-256 of the method, path, query and body of the first request
+	StatusCode   *int   // nil while the first request is still being processed
+	ContentType  string
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// IsCompleted reports whether the response is stored and can be replayed
+func (r *IdempotencyRecord) IsCompleted() bool {
+	return r.StatusCode != nil
+}
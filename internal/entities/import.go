@@ -0,0 +1,39 @@
+// file: internal/entities/import.go
+package entities
+
+// Fields of a coffee entry an import can fill, and the default source column of each
+const (
+	ImportFieldTimestamp = "timestamp"
+	ImportFieldType      = "type" // coffee type name (in the import language) or id
+	ImportFieldSize      = "size" // coffee size name (in the import language) or id
+	ImportFieldNotes     = "notes"
+	ImportFieldLatitude  = "latitude"
+	ImportFieldLongitude = "longitude"
+	ImportFieldCaffeine  = "caffeine_mg"
+	ImportFieldPrice     = "price"
+	ImportFieldCurrency  = "currency"
+	ImportFieldRating    = "rating"
+)
+
+// ImportFields lists every field an import can fill
+var ImportFields = []string{
+	ImportFieldTimestamp, ImportFieldType, ImportFieldSize, ImportFieldNotes, ImportFieldLatitude,
+	ImportFieldLongitude, ImportFieldCaffeine, ImportFieldPrice, ImportFieldCurrency, ImportFieldRating,
+}
+
+// ImportRowError is a problem with one imported row. Row is 1-based (the CSV header is not counted);
+// row 0 is the file itself, e.g. a missing column.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportReport is the outcome of an import. Nothing is imported unless every row is valid.
+type ImportReport struct {
+	DryRun   bool             `json:"dry_run"`
+	Rows     int              `json:"rows"`
+	Valid    int              `json:"valid"`
+	Imported int              `json:"imported"`
+	Errors   []ImportRowError `json:"errors"`
+}
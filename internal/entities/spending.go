@@ -0,0 +1,49 @@
+// file: internal/entities/spending.go
+package entities
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// MaxPricePerEntry caps the price of a single entry to catch typos (e.g. 450 instead of 4.50)
+const MaxPricePerEntry = 1000.0
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// NormalizeCurrency upper-cases and validates an ISO 4217 currency code ("eur" -> "EUR")
+func NormalizeCurrency(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return code, currencyCodePattern.MatchString(code)
+}
+
+// RoundMoney rounds an amount to cents
+func RoundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// CurrencySpending is the amount spent in a single currency.
+// Amounts in different currencies are never added together.
+type CurrencySpending struct {
+	Currency      string  `json:"currency"`
+	Total         float64 `json:"total"`
+	Entries       int     `json:"entries"`         // entries with a price
+	AveragePerCup float64 `json:"average_per_cup"` // Total / Entries
+}
+
+// SpendingPeriod holds the spending of one bucket; Date is the bucket's first day in the user's timezone.
+type SpendingPeriod struct {
+	Date       string             `json:"date"`
+	Currencies []CurrencySpending `json:"currencies"`
+}
+
+// SpendingReport is a zero-filled, time-bucketed spending series with per-currency totals
+type SpendingReport struct {
+	From     string             `json:"from"`
+	To       string             `json:"to"`
+	Bucket   StatsBucket        `json:"bucket"`
+	Timezone string             `json:"tz"`
+	Totals   []CurrencySpending `json:"totals"`
+	Periods  []SpendingPeriod   `json:"periods"`
+}
@@ -0,0 +1,35 @@
+// file: internal/entities/stats_series.go
+package entities
+
+// StatsBucket is the width of a time bucket in a statistics series
+type StatsBucket string
+
+const (
+	BucketDay   StatsBucket = "day"
+	BucketWeek  StatsBucket = "week" // ISO weeks, starting on Monday
+	BucketMonth StatsBucket = "month"
+)
+
+func (b StatsBucket) IsValid() bool {
+	switch b {
+	case BucketDay, BucketWeek, BucketMonth:
+		return true
+	}
+	return false
+}
+
+// SeriesPoint holds the totals of one bucket; Date is the bucket's first day in the user's timezone.
+type SeriesPoint struct {
+	Date          string `json:"date"`
+	Entries       int    `json:"entries"`
+	TotalCaffeine int    `json:"total_caffeine_mg"`
+}
+
+// StatsSeries is a zero-filled, time-bucketed series of entry counts
+type StatsSeries struct {
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	Bucket   StatsBucket   `json:"bucket"`
+	Timezone string        `json:"tz"`
+	Points   []SeriesPoint `json:"points"`
+}
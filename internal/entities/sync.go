@@ -0,0 +1,52 @@
+// file: internal/entities/sync.go
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EntryTombstone records a deleted entry so other devices can learn about the delete
+type EntryTombstone struct {
+	ID        uuid.UUID `json:"id"`
+	DeletedAt time.Time `json:"deleted_at"`
+	Version   int64     `json:"-"`
+}
+
+// SyncChanges is one page of the change feed since a sync token. Each entry appears at most
+// once, in the list matching its latest change.
+type SyncChanges struct {
+	Created   []*CoffeeEntry   `json:"created"`
+	Updated   []*CoffeeEntry   `json:"updated"`
+	Deleted   []EntryTombstone `json:"deleted"`
+	NextToken string           `json:"next_token"` // pass as "since" to get the following changes
+	HasMore   bool             `json:"has_more"`
+}
+
+// SyncOp is the kind of a client mutation
+type SyncOp string
+
+const (
+	SyncOpUpsert SyncOp = "upsert"
+	SyncOpDelete SyncOp = "delete"
+)
+
+// SyncStatus is the outcome of a single client mutation
+type SyncStatus string
+
+const (
+	SyncApplied   SyncStatus = "applied"   // the mutation is now the server state
+	SyncUnchanged SyncStatus = "unchanged" // already applied, e.g. a replayed mutation
+	SyncConflict  SyncStatus = "conflict"  // the server has a newer change, which is returned instead
+	SyncRejected  SyncStatus = "rejected"  // invalid mutation, nothing was changed
+)
+
+// SyncMutationResult reports what happened to one client mutation
+type SyncMutationResult struct {
+	ID      uuid.UUID    `json:"id"`
+	Status  SyncStatus   `json:"status"`
+	Entry   *CoffeeEntry `json:"entry,omitempty"`   // server copy after an upsert or on conflict
+	Deleted bool         `json:"deleted,omitempty"` // on conflict: the server copy is a delete
+	Error   string       `json:"error,omitempty"`
+}
@@ -13,14 +13,15 @@ type UserStatus struct {
 }
 
 type User struct {
-	ID        uuid.UUID  `db:"id" json:"id"`
-	Email     string     `db:"email" json:"email"`
-	Mobile    string     `db:"mobile" json:"mobile"`
-	Name      string     `db:"name" json:"name"`
-	AvatarURL string     `db:"avatar_url" json:"avatar_url"`
-	StatusID  int        `db:"status_id" json:"status_id"`
-	CreatedAt time.Time  `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+	ID                  uuid.UUID  `db:"id" json:"id"`
+	Email               string     `db:"email" json:"email"`
+	Mobile              string     `db:"mobile" json:"mobile"`
+	Name                string     `db:"name" json:"name"`
+	AvatarURL           string     `db:"avatar_url" json:"avatar_url"`
+	StatusID            int        `db:"status_id" json:"status_id"`
+	CreatedAt           time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt           time.Time  `db:"updated_at" json:"updated_at"`
+	DeletionScheduledAt *time.Time `db:"deletion_scheduled_at" json:"deletion_scheduled_at,omitempty"` // set while a deleted account awaits its purge
 }
 const (
 	StatusPending   = 1
@@ -35,4 +36,9 @@ const (
 // Business logic check
 func (u User) IsActive() bool {
 	return u.StatusID == StatusActive
+}
+
+// IsDeleted reports whether the account was deleted and awaits its purge
+func (u User) IsDeleted() bool {
+	return u.StatusID == StatusDeleted
 }
\ No newline at end of file
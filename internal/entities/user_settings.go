@@ -5,12 +5,15 @@ import "time"
 
 // UserSettings entity mapped to DB
 type UserSettings struct {
-    UserID               string    `db:"user_id"`
-    BiometricEnabled     bool      `db:"biometric_enabled"`
-    DarkMode             bool      `db:"dark_mode"`
-    NotificationsEnabled bool      `db:"notifications_enabled"`
-    CreatedAt            time.Time `db:"created_at"`
-    UpdatedAt            time.Time `db:"updated_at"`
+    UserID                  string    `db:"user_id"`
+    BiometricEnabled        bool      `db:"biometric_enabled"`
+    DarkMode                bool      `db:"dark_mode"`
+    NotificationsEnabled    bool      `db:"notifications_enabled"`
+    CaffeineHalfLifeMinutes int       `db:"caffeine_half_life_minutes"`
+    DailyCaffeineLimitMg    *int      `db:"daily_caffeine_limit_mg"`
+    DailyCupLimit           *int      `db:"daily_cup_limit"`
+    CreatedAt               time.Time `db:"created_at"`
+    UpdatedAt               time.Time `db:"updated_at"`
 }
 
 // Enum-like type for allowed settings
@@ -21,13 +24,42 @@ const (
 	SettingBiometricEnabled
 	SettingDarkMode
 	SettingNotificationsEnabled
+	SettingCaffeineHalfLife
+	SettingDailyCaffeineLimit
+	SettingDailyCupLimit
 )
 
+// Caffeine half-life bounds (minutes). The commonly cited adult average is ~5 hours.
+const (
+	DefaultCaffeineHalfLifeMinutes = 300
+	MinCaffeineHalfLifeMinutes     = 60
+	MaxCaffeineHalfLifeMinutes     = 24 * 60
+)
+
+// Upper bounds for the daily limits, a null limit means "no limit"
+const (
+	MaxDailyCaffeineLimitMg = 5000
+	MaxDailyCupLimit        = 50
+)
+
+// AllSettings lists every setting, e.g. to create the defaults of a new user
+var AllSettings = []Setting{
+	SettingBiometricEnabled,
+	SettingDarkMode,
+	SettingNotificationsEnabled,
+	SettingCaffeineHalfLife,
+	SettingDailyCaffeineLimit,
+	SettingDailyCupLimit,
+}
+
 func (s Setting) IsValid() bool {
 	switch s {
 	case SettingBiometricEnabled,
 		SettingDarkMode,
-		SettingNotificationsEnabled:
+		SettingNotificationsEnabled,
+		SettingCaffeineHalfLife,
+		SettingDailyCaffeineLimit,
+		SettingDailyCupLimit:
 		return true
 	}
 	return false
@@ -41,7 +73,62 @@ func (s Setting) ColumnName() string {
 		return "dark_mode"
 	case SettingNotificationsEnabled:
 		return "notifications_enabled"
+	case SettingCaffeineHalfLife:
+		return "caffeine_half_life_minutes"
+	case SettingDailyCaffeineLimit:
+		return "daily_caffeine_limit_mg"
+	case SettingDailyCupLimit:
+		return "daily_cup_limit"
 	default:
 		return ""
 	}
+}
+
+// DefaultValue is the value a setting is reset to
+func (s Setting) DefaultValue() interface{} {
+	switch s {
+	case SettingCaffeineHalfLife:
+		return DefaultCaffeineHalfLifeMinutes
+	case SettingDailyCaffeineLimit, SettingDailyCupLimit:
+		return nil
+	default:
+		return false
+	}
+}
+
+// NormalizeValue checks a client-supplied value (as decoded from JSON) against the setting's type
+// and range, and returns it in the form stored in the DB.
+func (s Setting) NormalizeValue(value interface{}) (interface{}, bool) {
+	switch s {
+	case SettingBiometricEnabled,
+		SettingDarkMode,
+		SettingNotificationsEnabled:
+		b, ok := value.(bool)
+		return b, ok
+	case SettingCaffeineHalfLife:
+		return normalizeIntInRange(value, MinCaffeineHalfLifeMinutes, MaxCaffeineHalfLifeMinutes)
+	case SettingDailyCaffeineLimit:
+		if value == nil {
+			return nil, true
+		}
+		return normalizeIntInRange(value, 1, MaxDailyCaffeineLimitMg)
+	case SettingDailyCupLimit:
+		if value == nil {
+			return nil, true
+		}
+		return normalizeIntInRange(value, 1, MaxDailyCupLimit)
+	}
+	return nil, false
+}
+
+// normalizeIntInRange accepts a whole JSON number within [min, max]
+func normalizeIntInRange(value interface{}, min, max int) (interface{}, bool) {
+	n, ok := value.(float64)
+	if !ok || n != float64(int(n)) {
+		return nil, false
+	}
+	if int(n) < min || int(n) > max {
+		return nil, false
+	}
+	return int(n), true
 }
\ No newline at end of file
@@ -79,6 +79,27 @@ func (s *JWTService) GenerateRefreshToken(userID uuid.UUID) (string, error) {
 	return token.SignedString([]byte(s.secret))
 }
 
+// GenerateConfirmationToken creates a short-lived JWT confirming a destructive operation.
+// The token is only valid for the given user and scope (e.g. the date range being cleared),
+// and is rejected on protected routes since it is not an access token.
+func (s *JWTService) GenerateConfirmationToken(userID uuid.UUID, scope string, ttl time.Duration) (string, time.Time, error) {
+	now := s.nowFunc()
+	expiresAt := now.Add(ttl)
+	claims := jwt.MapClaims{
+		"sub":   userID.String(),
+		"aud":   "authenticated",
+		"exp":   expiresAt.Unix(),
+		"iat":   now.Unix(),
+		"type":  "confirmation",
+		"scope": scope,
+		"jti":   uuid.New().String(),
+	}
+
+	token := jwt.NewWithClaims(s.signingMethod, claims)
+	signed, err := token.SignedString([]byte(s.secret))
+	return signed, expiresAt, err
+}
+
 //
 // ===========================
 // 🔍 Token Validation
@@ -129,7 +150,7 @@ func (s *JWTService) ExtractUserIDFromToken(tokenString string) (uuid.UUID, erro
 	return uuid.Parse(sub)
 }
 
-// TokenType returns the type of token ("access" or "refresh")
+// TokenType returns the type of token ("access", "refresh" or "confirmation")
 func (s *JWTService) TokenType(claims jwt.MapClaims) string {
 	if t, ok := claims["type"].(string); ok {
 		return t
@@ -142,6 +163,36 @@ func (s *JWTService) IsRefreshToken(claims jwt.MapClaims) bool {
 	return s.TokenType(claims) == "refresh"
 }
 
+// IsAccessToken returns true if claims belong to an access token
+func (s *JWTService) IsAccessToken(claims jwt.MapClaims) bool {
+	return s.TokenType(claims) == "access"
+}
+
+// ValidateConfirmationToken checks that tokenString is an unexpired confirmation token
+// issued to userID for exactly the given scope. It returns the token's jti and expiry, for the
+// caller to record the token as used.
+func (s *JWTService) ValidateConfirmationToken(tokenString string, userID uuid.UUID, scope string) (string, time.Time, error) {
+	claims, err := s.ValidateTokenString(tokenString)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if s.TokenType(claims) != "confirmation" {
+		return "", time.Time{}, ErrInvalidToken
+	}
+	if sub, _ := claims["sub"].(string); sub != userID.String() {
+		return "", time.Time{}, ErrInvalidClaims
+	}
+	if tokenScope, _ := claims["scope"].(string); tokenScope != scope {
+		return "", time.Time{}, ErrInvalidClaims
+	}
+	jti, _ := claims["jti"].(string)
+	expiresAt, err := claims.GetExpirationTime()
+	if jti == "" || err != nil || expiresAt == nil {
+		return "", time.Time{}, ErrInvalidClaims
+	}
+	return jti, expiresAt.Time, nil
+}
+
 // ParseAndValidate is a helper that returns both userID and claims
 func (s *JWTService) ParseAndValidate(tokenString string) (uuid.UUID, jwt.MapClaims, error) {
 	claims, err := s.ValidateTokenString(tokenString)
@@ -14,6 +14,9 @@ type TokenService interface {
 	ValidateTokenString(tokenString string) (jwt.MapClaims, error)
 	ExtractUserIDFromToken(tokenString string) (uuid.UUID, error)
 	IsRefreshToken(claims jwt.MapClaims) bool
+	IsAccessToken(claims jwt.MapClaims) bool
+	GenerateConfirmationToken(userID uuid.UUID, scope string, ttl time.Duration) (string, time.Time, error)
+	ValidateConfirmationToken(tokenString string, userID uuid.UUID, scope string) (string, time.Time, error)
 	AccessExpiry() time.Duration
 	RefreshExpiry() time.Duration
 }
\ No newline at end of file
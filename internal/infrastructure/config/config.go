@@ -5,9 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/netip"
 	"os"
+	"strings"
 	"time"
 
+	"coffee-tracker-backend/internal/infrastructure/ratelimit"
+
 	"github.com/joho/godotenv"
 )
 
@@ -19,17 +23,28 @@ const (
 )
 
 type Config struct {
-	Env                string
-	Port               string
-	DatabaseURL        string
-	JWTSecret          string
-	OtpStrength        OtpStrength
-	MagicOtp           string
-	StorageURL         string
-	ServiceRoleKey     string
-	ProfileImageBucket string
-	AccessTokenTTL     time.Duration
-	RefreshTokenTTL    time.Duration
+	Env                  string
+	Port                 string
+	DatabaseURL          string
+	JWTSecret            string
+	OtpStrength          OtpStrength
+	MagicOtp             string
+	StorageURL           string
+	ServiceRoleKey       string
+	ProfileImageBucket   string
+	DataExportBucket     string        // private bucket for account data export archives
+	DataExportLinkTTL    time.Duration // lifetime of an archive download link
+	AccessTokenTTL       time.Duration
+	RefreshTokenTTL      time.Duration
+	IdempotencyTTL       time.Duration   // how long Idempotency-Key responses are replayed
+	TrashRetention       time.Duration   // how long deleted entries stay restorable
+	PurgeInterval        time.Duration   // how often expired data is purged
+	AccountDeletionGrace time.Duration   // how long a deleted account can be restored by logging in
+	RateLimitAuthIP      ratelimit.Limit // OTP sign-in and sign-up requests per client IP
+	RateLimitOtpMobile   ratelimit.Limit // OTP sends per mobile number
+	RateLimitUser        ratelimit.Limit // authenticated requests per user; off by default
+	TrustedProxies       []netip.Prefix  // proxies whose X-Forwarded-For is believed; none by default
+	ClientIPHeader       string          // header holding the client address set by the platform's proxy, e.g. Fly-Client-IP
 }
 
 func Load() (*Config, error) {
@@ -40,6 +55,11 @@ func Load() (*Config, error) {
 
 	accessTTL := 15 * time.Minute
 	refreshTTL := 7 * 24 * time.Hour
+	idempotencyTTL := 24 * time.Hour
+	trashRetention := 30 * 24 * time.Hour
+	purgeInterval := time.Hour
+	dataExportLinkTTL := 24 * time.Hour
+	accountDeletionGrace := 30 * 24 * time.Hour
 
 	if v := os.Getenv("ACCESS_TOKEN_TTL"); v != "" {
 		if dur, err := time.ParseDuration(v); err == nil {
@@ -55,19 +75,87 @@ func Load() (*Config, error) {
 			return nil, fmt.Errorf("invalid REFRESH_TOKEN_TTL: %v", err)
 		}
 	}
+	if v := os.Getenv("IDEMPOTENCY_TTL"); v != "" {
+		if dur, err := time.ParseDuration(v); err == nil {
+			idempotencyTTL = dur
+		} else {
+			return nil, fmt.Errorf("invalid IDEMPOTENCY_TTL: %v", err)
+		}
+	}
+	if v := os.Getenv("TRASH_RETENTION"); v != "" {
+		if dur, err := time.ParseDuration(v); err == nil {
+			trashRetention = dur
+		} else {
+			return nil, fmt.Errorf("invalid TRASH_RETENTION: %v", err)
+		}
+	}
+	if v := os.Getenv("PURGE_INTERVAL"); v != "" {
+		if dur, err := time.ParseDuration(v); err == nil {
+			purgeInterval = dur
+		} else {
+			return nil, fmt.Errorf("invalid PURGE_INTERVAL: %v", err)
+		}
+	}
+	if v := os.Getenv("DATA_EXPORT_LINK_TTL"); v != "" {
+		if dur, err := time.ParseDuration(v); err == nil {
+			dataExportLinkTTL = dur
+		} else {
+			return nil, fmt.Errorf("invalid DATA_EXPORT_LINK_TTL: %v", err)
+		}
+	}
+	if v := os.Getenv("ACCOUNT_DELETION_GRACE"); v != "" {
+		if dur, err := time.ParseDuration(v); err == nil {
+			accountDeletionGrace = dur
+		} else {
+			return nil, fmt.Errorf("invalid ACCOUNT_DELETION_GRACE: %v", err)
+		}
+	}
+
+	rateLimitAuthIP, err := ratelimit.ParseLimit(getEnv("RATE_LIMIT_AUTH_IP", "30/1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_AUTH_IP: %v", err)
+	}
+	rateLimitOtpMobile, err := ratelimit.ParseLimit(getEnv("RATE_LIMIT_OTP_MOBILE", "5/1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_OTP_MOBILE: %v", err)
+	}
+	rateLimitUser, err := ratelimit.ParseLimit(getEnv("RATE_LIMIT_USER", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_USER: %v", err)
+	}
+
+	trustedProxies, err := parseTrustedProxies(getEnv("TRUSTED_PROXIES", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %v", err)
+	}
+	clientIPHeader := getEnv("CLIENT_IP_HEADER", "")
+	if os.Getenv("FLY_APP_NAME") != "" && len(trustedProxies) == 0 && clientIPHeader == "" {
+		log.Println("⚠️ Running on Fly without TRUSTED_PROXIES or CLIENT_IP_HEADER: every client gets the proxy's address, so per-IP limits apply to all clients together")
+	}
 
 	cfg := &Config{
-		Env:                getEnv("ENV", "dev"),
-		Port:               getEnv("PORT", "8080"),
-		DatabaseURL:        getEnv("DATABASE_URL", ""),
-		JWTSecret:          getEnv("JWT_SECRET", ""),
-		OtpStrength:        OtpStrength(getEnv("OTP_STRENGTH", "easy")),
-		MagicOtp:           getEnv("MAGIC_OTP", ""),
-		StorageURL:         getEnv("SUPABASE_STORAGE_URL", ""),
-		ServiceRoleKey:     getEnv("SUPABASE_SERVICE_KEY_ID", ""),
-		ProfileImageBucket: getEnv("PROFILE_IMAGE_BUCKET", ""),
-		AccessTokenTTL:     accessTTL,
-		RefreshTokenTTL:    refreshTTL,
+		Env:                  getEnv("ENV", "dev"),
+		Port:                 getEnv("PORT", "8080"),
+		DatabaseURL:          getEnv("DATABASE_URL", ""),
+		JWTSecret:            getEnv("JWT_SECRET", ""),
+		OtpStrength:          OtpStrength(getEnv("OTP_STRENGTH", "easy")),
+		MagicOtp:             getEnv("MAGIC_OTP", ""),
+		StorageURL:           getEnv("SUPABASE_STORAGE_URL", ""),
+		ServiceRoleKey:       getEnv("SUPABASE_SERVICE_KEY_ID", ""),
+		ProfileImageBucket:   getEnv("PROFILE_IMAGE_BUCKET", ""),
+		DataExportBucket:     getEnv("DATA_EXPORT_BUCKET", "data-exports"),
+		DataExportLinkTTL:    dataExportLinkTTL,
+		AccessTokenTTL:       accessTTL,
+		RefreshTokenTTL:      refreshTTL,
+		IdempotencyTTL:       idempotencyTTL,
+		TrashRetention:       trashRetention,
+		PurgeInterval:        purgeInterval,
+		AccountDeletionGrace: accountDeletionGrace,
+		RateLimitAuthIP:      rateLimitAuthIP,
+		RateLimitOtpMobile:   rateLimitOtpMobile,
+		RateLimitUser:        rateLimitUser,
+		TrustedProxies:       trustedProxies,
+		ClientIPHeader:       clientIPHeader,
 	}
 
 	// Validate immediately
@@ -78,6 +166,31 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// parseTrustedProxies reads a comma-separated list of CIDRs or single addresses
+func parseTrustedProxies(s string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if !strings.Contains(part, "/") {
+			addr, err := netip.ParseAddr(part)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(part)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -133,6 +246,21 @@ func (c *Config) validate() error {
 	if c.RefreshTokenTTL <= 0 {
 		return errors.New("REFRESH_TOKEN_TTL must be greater than 0")
 	}
+	if c.IdempotencyTTL <= 0 {
+		return errors.New("IDEMPOTENCY_TTL must be greater than 0")
+	}
+	if c.TrashRetention <= 0 {
+		return errors.New("TRASH_RETENTION must be greater than 0")
+	}
+	if c.PurgeInterval <= 0 {
+		return errors.New("PURGE_INTERVAL must be greater than 0")
+	}
+	if c.DataExportLinkTTL <= 0 {
+		return errors.New("DATA_EXPORT_LINK_TTL must be greater than 0")
+	}
+	if c.AccountDeletionGrace <= 0 {
+		return errors.New("ACCOUNT_DELETION_GRACE must be greater than 0")
+	}
 
 	return nil
 }
@@ -2,6 +2,7 @@
 package handlers
 
 import (
+	"coffee-tracker-backend/internal/entities"
 	"coffee-tracker-backend/internal/infrastructure/auth"
 	http_utils "coffee-tracker-backend/internal/infrastructure/http"
 
@@ -24,6 +25,9 @@ type AuthHandler struct {
 	saveRefreshTokenUC    *usecases.SaveRefreshTokenUseCase
 	getRefreshTokenUC     *usecases.GetRefreshTokenUseCase
 	deleteRefreshTokenUC  *usecases.DeleteRefreshTokenUseCase
+	cancelDeletionUC      *usecases.CancelAccountDeletionUseCase
+	registerUserUC        *usecases.RegisterUserUseCase
+	activateUserUC        *usecases.ActivateUserUseCase
 }
 
 func NewAuthHandler(
@@ -35,6 +39,9 @@ func NewAuthHandler(
 	saveRefreshTokenUC *usecases.SaveRefreshTokenUseCase,
 	getRefreshTokenUC *usecases.GetRefreshTokenUseCase,
 	deleteRefreshTokenUC *usecases.DeleteRefreshTokenUseCase,
+	cancelDeletionUC *usecases.CancelAccountDeletionUseCase,
+	registerUserUC *usecases.RegisterUserUseCase,
+	activateUserUC *usecases.ActivateUserUseCase,
 ) *AuthHandler {
 	if tokenService == nil {
 		log.Fatal("JWT service is required")
@@ -48,6 +55,9 @@ func NewAuthHandler(
 		saveRefreshTokenUC:   saveRefreshTokenUC,
 		getRefreshTokenUC:    getRefreshTokenUC,
 		deleteRefreshTokenUC: deleteRefreshTokenUC,
+		cancelDeletionUC:     cancelDeletionUC,
+		registerUserUC:       registerUserUC,
+		activateUserUC:       activateUserUC,
 	}
 }
 
@@ -77,6 +87,33 @@ func (h *AuthHandler) RequestOTP(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// POST /auth/register
+// Sends an OTP to a new mobile number; verifying it with /auth/verify-otp activates the account
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	http_utils.LogRequest(r)
+
+	var req models.RegisterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Mobile == "" {
+		http_utils.WriteError(w, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := h.registerUserUC.Execute(r.Context(), req.Mobile, req.Name); err != nil {
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "Invalid mobile number")
+		case usecases.ErrConflict:
+			http_utils.WriteError(w, http.StatusConflict, "Mobile number already registered")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to generate OTP")
+		}
+		return
+	}
+	http_utils.WriteJSON(w, http.StatusOK, models.SendOtpResponse{
+		Message: "OTP sent successfully",
+	})
+}
+
 // POST /auth/verify-otp
 func (h *AuthHandler) VerifyOTP(w http.ResponseWriter, r *http.Request) {
 	http_utils.LogRequest(r)
@@ -98,12 +135,42 @@ func (h *AuthHandler) VerifyOTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	valid, err := h.validateOtpUC.Execute(r.Context(), user.ID, req.OTP)
-	if err != nil || !valid {
-		http_utils.WriteError(w, http.StatusUnauthorized, "Invalid or expired OTP")
+	retryAfter, err := h.validateOtpUC.Execute(r.Context(), user.ID, req.OTP, http_utils.GetUserIpAddress(r))
+	if err != nil {
+		switch err {
+		case usecases.ErrTooManyAttempts:
+			http_utils.SetRetryAfter(w, retryAfter)
+			http_utils.WriteError(w, http.StatusTooManyRequests, "Too many attempts, try again later")
+		case usecases.ErrInvalidOTP:
+			http_utils.WriteError(w, http.StatusUnauthorized, "Invalid or expired OTP")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to verify OTP")
+		}
 		return
 	}
 
+	switch user.StatusID {
+	case entities.StatusPending:
+		// Verifying the number completes the sign-up
+		if err := h.activateUserUC.Execute(r.Context(), user.ID); err != nil {
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to activate account")
+			return
+		}
+		log.Printf("[VERIFY-OTP] ✅ Activated userID=%s", user.ID)
+	case entities.StatusDeleted:
+		// Logging in during the grace period cancels a pending account deletion
+		if err := h.cancelDeletionUC.Execute(r.Context(), user.ID); err != nil {
+			switch err {
+			case usecases.ErrUserNotFound:
+				http_utils.WriteError(w, http.StatusUnauthorized, "User not found")
+			default:
+				http_utils.WriteError(w, http.StatusInternalServerError, "Failed to restore account")
+			}
+			return
+		}
+		log.Printf("[VERIFY-OTP] ♻️ Cancelled the deletion of userID=%s", user.ID)
+	}
+
 	accessToken, err := h.tokenService.GenerateAccessToken(user.ID)
 	if err != nil {
 		http_utils.WriteError(w, http.StatusInternalServerError, "Failed to generate access token")
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -17,6 +18,8 @@ type CoffeeEntryHandler struct {
 	deleteUC    *usecases.DeleteCoffeeEntryUseCase
 	clearUC     *usecases.ClearCoffeeEntriesUseCase
 	getStatsUC  *usecases.GetCoffeeStatsUseCase
+	searchUC    *usecases.SearchCoffeeEntriesUseCase
+	batchUC     *usecases.BatchCoffeeEntriesUseCase
 }
 
 func NewCoffeeEntryHandler(
@@ -26,6 +29,8 @@ func NewCoffeeEntryHandler(
 	deleteUC *usecases.DeleteCoffeeEntryUseCase,
 	clearUC *usecases.ClearCoffeeEntriesUseCase,
 	getStatsUC *usecases.GetCoffeeStatsUseCase,
+	searchUC *usecases.SearchCoffeeEntriesUseCase,
+	batchUC *usecases.BatchCoffeeEntriesUseCase,
 ) *CoffeeEntryHandler {
 	return &CoffeeEntryHandler{
 		createUC:   createUC,
@@ -34,6 +39,8 @@ func NewCoffeeEntryHandler(
 		deleteUC:   deleteUC,
 		clearUC:    clearUC,
 		getStatsUC: getStatsUC,
+		searchUC:   searchUC,
+		batchUC:    batchUC,
 	}
 }
 
@@ -48,44 +55,197 @@ func (h *CoffeeEntryHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	entry, err := h.createUC.Execute(r.Context(), userID, &req)
+	entry, limitStatus, err := h.createUC.Execute(r.Context(), userID, &req)
 	if err != nil {
-		http_utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	http_utils.WriteJSON(w, http.StatusCreated, models.CreateCoffeeEntryResponse{
+		CoffeeEntry: entry,
+		DailyLimit:  limitStatus,
+	})
+}
+
+// POST /entries:batch
+func (h *CoffeeEntryHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok { return }
+
+	var req models.BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.batchUC.Execute(r.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case results != nil:
+			// Nothing was applied; the results point at the failed operations
+			status := http.StatusUnprocessableEntity
+			if err == usecases.ErrNotFound {
+				status = http.StatusNotFound
+			}
+			http_utils.WriteJSON(w, status, map[string]any{
+				"error":   "Batch rolled back: " + err.Error(),
+				"status":  status,
+				"success": false,
+				"results": results,
+			})
+		case err == usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Expected 1 to %d operations", usecases.MaxBatchOperations))
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to apply batch")
+		}
 		return
 	}
 
-	http_utils.WriteJSON(w, http.StatusCreated, entry)
+	http_utils.WriteJSON(w, http.StatusOK, map[string]any{
+		"results": results,
+	})
 }
 
-// GET /entries
+// GET /entries?from=2025-08-01&to=2025-08-31&tzOffset=180&type=2&size=1&has_notes=true&has_location=false&sort=-timestamp&limit=50&offset=0
+// (date=2025-08-21 selects a single day)
+// Passing cursor (empty for the first page) switches to keyset pagination and a {items, next_cursor} envelope.
 func (h *CoffeeEntryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 
 	userID, ok := http_utils.GetUserIDOrAbort(w, r)
 	if !ok { return }
 
-	dateStr := r.URL.Query().Get("date")
-	tzOffsetStr := r.URL.Query().Get("tzOffset")
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
+	query, err := parseListEntriesQuery(r)
+	if err != nil {
+		http_utils.WriteError(w, http.StatusBadRequest, "Invalid query parameters", err.Error())
+		return
+	}
 
-	limit, _ := strconv.Atoi(limitStr)
-	offset, _ := strconv.Atoi(offsetStr)
+	entries, nextCursor, err := h.getAllUC.Execute(r.Context(), userID, query)
+	if err != nil {
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "Invalid date/from/to (YYYY-MM-DD), sort, limit or cursor")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to get entries")
+		}
+		return
+	}
 
+	if query.Cursor != nil {
+		http_utils.WriteJSON(w, http.StatusOK, models.CoffeeEntriesPage{
+			Items:      entries,
+			NextCursor: nextCursor,
+		})
+		return
+	}
 
-	var tzOffset *int
-	if tzOffsetStr != "" {
-		if offset, err := strconv.Atoi(tzOffsetStr); err == nil {
-			tzOffset = &offset
-		}
+	http_utils.WriteJSON(w, http.StatusOK, entries)
+}
+
+// GET /entries/search?q="oat milk" lisbon&limit=20
+func (h *CoffeeEntryHandler) Search(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok { return }
+
+	limit, err := optionalInt(r.URL.Query().Get("limit"))
+	if err != nil {
+		http_utils.WriteError(w, http.StatusBadRequest, "'limit' must be a number")
+		return
+	}
+	if limit == nil {
+		limit = new(int)
 	}
 
-	entries, err := h.getAllUC.Execute(r.Context(), userID, &dateStr, tzOffset, limit, offset)
+	results, err := h.searchUC.Execute(r.Context(), userID, r.URL.Query().Get("q"), *limit)
 	if err != nil {
-		http_utils.WriteError(w, http.StatusInternalServerError, "Failed to get entries")
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "'q' is required (max 200 characters) and 'limit' must be at most 100")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to search entries")
+		}
 		return
 	}
 
-	http_utils.WriteJSON(w, http.StatusOK, entries)
+	http_utils.WriteJSON(w, http.StatusOK, map[string]any{
+		"results": results,
+	})
+}
+
+// parseListEntriesQuery reads the GET /entries query string; malformed numbers or booleans are an error
+func parseListEntriesQuery(r *http.Request) (*models.ListCoffeeEntriesQuery, error) {
+	q := r.URL.Query()
+	query := &models.ListCoffeeEntriesQuery{
+		Date: q.Get("date"),
+		From: q.Get("from"),
+		To:   q.Get("to"),
+		Sort: q.Get("sort"),
+	}
+	if q.Has("cursor") {
+		cursor := q.Get("cursor")
+		query.Cursor = &cursor
+	}
+
+	var err error
+	if query.TzOffset, err = optionalInt(q.Get("tzOffset")); err != nil {
+		return nil, err
+	}
+	if query.CoffeeType, err = optionalInt(q.Get("type")); err != nil {
+		return nil, err
+	}
+	if query.Size, err = optionalInt(q.Get("size")); err != nil {
+		return nil, err
+	}
+	if query.HasNotes, err = optionalBool(q.Get("has_notes")); err != nil {
+		return nil, err
+	}
+	if query.HasLocation, err = optionalBool(q.Get("has_location")); err != nil {
+		return nil, err
+	}
+
+	limit, err := optionalInt(q.Get("limit"))
+	if err != nil {
+		return nil, err
+	}
+	if limit != nil {
+		query.Limit = *limit
+	}
+	offset, err := optionalInt(q.Get("offset"))
+	if err != nil {
+		return nil, err
+	}
+	if offset != nil {
+		query.Offset = *offset
+	}
+
+	return query, nil
+}
+
+func optionalInt(value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func optionalBool(value string) (*bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
 }
 
 // PATCH /entries/{id}
@@ -147,26 +307,90 @@ func (h *CoffeeEntryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// DELETE /entries
+// confirmationTokenHeader carries the token from POST /entries/clear-confirmation on DELETE /entries
+const confirmationTokenHeader = "X-Confirmation-Token"
+
+// POST /entries/clear-confirmation?from=2025-08-01&to=2025-08-31&tzOffset=180
+func (h *CoffeeEntryHandler) ClearConfirmation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok { return }
+
+	query, err := parseClearEntriesQuery(r)
+	if err != nil {
+		http_utils.WriteError(w, http.StatusBadRequest, "Invalid query parameters", err.Error())
+		return
+	}
+
+	token, expiresAt, count, err := h.clearUC.RequestConfirmation(r.Context(), userID, query)
+	if err != nil {
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "Invalid date range")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to create confirmation token")
+		}
+		return
+	}
+
+	http_utils.WriteJSON(w, http.StatusOK, models.ClearConfirmationResponse{
+		ConfirmationToken: token,
+		ExpiresAt:         expiresAt,
+		Entries:           count,
+	})
+}
+
+// DELETE /entries?from=2025-08-01&to=2025-08-31&tzOffset=180
+// Requires the X-Confirmation-Token header, issued for the same range
 func (h *CoffeeEntryHandler) ClearAll(w http.ResponseWriter, r *http.Request) {
 	userID, ok := http_utils.GetUserIDOrAbort(w, r)
 	if !ok { return }
 
-	err := h.clearUC.Execute(r.Context(), userID)
+	query, err := parseClearEntriesQuery(r)
 	if err != nil {
-		http_utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		http_utils.WriteError(w, http.StatusBadRequest, "Invalid query parameters", err.Error())
 		return
 	}
+	query.ConfirmationToken = r.Header.Get(confirmationTokenHeader)
 
-	w.WriteHeader(http.StatusNoContent)
+	deleted, err := h.clearUC.Execute(r.Context(), userID, query)
+	if err != nil {
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "Invalid date range")
+		case usecases.ErrConfirmationRequired:
+			http_utils.WriteError(w, http.StatusPreconditionRequired, "Confirmation token required, request one from POST /entries/clear-confirmation")
+		case usecases.ErrInvalidConfirmation:
+			http_utils.WriteError(w, http.StatusForbidden, "Invalid or expired confirmation token for this range")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to clear entries")
+		}
+		return
+	}
+
+	http_utils.WriteJSON(w, http.StatusOK, models.ClearCoffeeEntriesResponse{Deleted: deleted})
+}
+
+// parseClearEntriesQuery reads the range of a clear; tzOffset must be a number
+func parseClearEntriesQuery(r *http.Request) (*models.ClearCoffeeEntriesQuery, error) {
+	q := r.URL.Query()
+	query := &models.ClearCoffeeEntriesQuery{
+		From: q.Get("from"),
+		To:   q.Get("to"),
+	}
+
+	var err error
+	if query.TzOffset, err = optionalInt(q.Get("tzOffset")); err != nil {
+		return nil, err
+	}
+	return query, nil
 }
 
-// GET /entries/stats
+// GET /stats?language=en
 func (h *CoffeeEntryHandler) GetStats(w http.ResponseWriter, r *http.Request) {
 	userID, ok := http_utils.GetUserIDOrAbort(w, r)
 	if !ok { return }
 
-	stats, err := h.getStatsUC.Execute(r.Context(), userID)
+	stats, err := h.getStatsUC.Execute(r.Context(), userID, r.URL.Query().Get("language"))
 	if err != nil {
 		http_utils.WriteError(w, http.StatusInternalServerError, "Failed to get stats")
 		return
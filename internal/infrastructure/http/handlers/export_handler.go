@@ -0,0 +1,258 @@
+// file: internal/infrastructure/http/handlers/export_handler.go
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+	http_utils "coffee-tracker-backend/internal/infrastructure/http"
+	"coffee-tracker-backend/internal/infrastructure/http/models"
+	"coffee-tracker-backend/internal/usecases"
+)
+
+// exportFlushEvery is the number of rows written between flushes of the response
+const exportFlushEvery = 100
+
+type ExportHandler struct {
+	exportUC *usecases.ExportCoffeeEntriesUseCase
+}
+
+func NewExportHandler(exportUC *usecases.ExportCoffeeEntriesUseCase) *ExportHandler {
+	return &ExportHandler{exportUC: exportUC}
+}
+
+// GET /entries/export?format=csv&from=2025-08-01&to=2025-08-31&tzOffset=180&language=en
+// format is csv (default), json or ndjson. Rows are streamed as they are read from the database.
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	format := q.Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	newWriter, ok := exportWriters[format]
+	if !ok {
+		http_utils.WriteError(w, http.StatusBadRequest, "Invalid format", "format must be csv, json or ndjson")
+		return
+	}
+
+	query := &models.ExportCoffeeEntriesQuery{
+		From:     q.Get("from"),
+		To:       q.Get("to"),
+		Language: q.Get("language"),
+	}
+	var err error
+	if query.TzOffset, err = optionalInt(q.Get("tzOffset")); err != nil {
+		http_utils.WriteError(w, http.StatusBadRequest, "Invalid query parameters", err.Error())
+		return
+	}
+
+	// The export may take longer than the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("export for user %s keeps the write timeout, a large export may be cut short: %v", userID, err)
+	}
+
+	writer := newWriter(w)
+	started := false
+	rows := 0
+	err = h.exportUC.Execute(r.Context(), userID, query, func(entry *entities.ExportedEntry) error {
+		if !started {
+			startExport(w, writer, format)
+			started = true
+		}
+		if err := writer.Write(entry); err != nil {
+			return err
+		}
+		rows++
+		if rows%exportFlushEvery == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			if err := rc.Flush(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		if started {
+			// Headers are gone already; all we can do is cut the response short
+			log.Printf("export for user %s failed after %d rows: %v", userID, rows, err)
+			return
+		}
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "Invalid date range")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to export entries")
+		}
+		return
+	}
+
+	if !started {
+		startExport(w, writer, format)
+	}
+	if err := writer.Close(); err != nil {
+		log.Printf("export for user %s failed after %d rows: %v", userID, rows, err)
+	}
+}
+
+// startExport sends the response headers and the format's preamble
+func startExport(w http.ResponseWriter, writer exportWriter, format string) {
+	w.Header().Set("Content-Type", exportContentTypes[format])
+	w.Header().Set("Content-Disposition", `attachment; filename="coffee-entries.`+format+`"`)
+	w.WriteHeader(http.StatusOK)
+	writer.Begin()
+}
+
+var exportContentTypes = map[string]string{
+	"csv":    "text/csv; charset=utf-8",
+	"json":   "application/json",
+	"ndjson": "application/x-ndjson",
+}
+
+// exportWriter writes entries in one export format
+type exportWriter interface {
+	Begin()
+	Write(entry *entities.ExportedEntry) error
+	Flush() error
+	Close() error // writes the closing part of the format and flushes
+}
+
+var exportWriters = map[string]func(io.Writer) exportWriter{
+	"csv":    newCSVExportWriter,
+	"json":   func(w io.Writer) exportWriter { return &jsonExportWriter{w: w} },
+	"ndjson": func(w io.Writer) exportWriter { return &jsonExportWriter{w: w, lines: true} },
+}
+
+// csvExportHeader lists the CSV columns, in order
+var csvExportHeader = []string{
+	"id", "timestamp", "type_id", "type", "size_id", "size", "caffeine_mg",
+	"price", "currency", "rating", "notes", "latitude", "longitude", "created_at", "updated_at",
+}
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func newCSVExportWriter(w io.Writer) exportWriter {
+	return &csvExportWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvExportWriter) Begin() {
+	_ = c.w.Write(csvExportHeader)
+}
+
+func (c *csvExportWriter) Write(e *entities.ExportedEntry) error {
+	return c.w.Write([]string{
+		e.ID.String(),
+		e.Timestamp.UTC().Format(time.RFC3339),
+		csvInt(e.CoffeeTypeID),
+		csvText(e.TypeName),
+		csvInt(e.SizeID),
+		csvText(e.SizeName),
+		csvInt(e.Caffeine),
+		csvFloat(e.Price),
+		csvString(e.Currency),
+		csvInt(e.Rating),
+		csvString(e.Notes),
+		csvFloat(e.Latitude),
+		csvFloat(e.Longitude),
+		e.CreatedAt.UTC().Format(time.RFC3339),
+		e.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (c *csvExportWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvExportWriter) Close() error {
+	return c.Flush()
+}
+
+func csvInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func csvFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+func csvString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return csvText(*v)
+}
+
+// csvText keeps spreadsheets from evaluating user text as a formula
+func csvText(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+// jsonExportWriter writes a JSON array, or one JSON object per line in ndjson mode
+type jsonExportWriter struct {
+	w     io.Writer
+	lines bool
+	count int
+}
+
+func (j *jsonExportWriter) Begin() {
+	if !j.lines {
+		_, _ = io.WriteString(j.w, "[")
+	}
+}
+
+func (j *jsonExportWriter) Write(e *entities.ExportedEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if !j.lines && j.count > 0 {
+		if _, err := io.WriteString(j.w, ","); err != nil {
+			return err
+		}
+	}
+	if j.lines {
+		data = append(data, '\n')
+	}
+	j.count++
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonExportWriter) Flush() error {
+	return nil
+}
+
+func (j *jsonExportWriter) Close() error {
+	if !j.lines {
+		_, err := io.WriteString(j.w, "]")
+		return err
+	}
+	return nil
+}
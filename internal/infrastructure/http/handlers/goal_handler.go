@@ -0,0 +1,128 @@
+// file: internal/infrastructure/http/handlers/goal_handler.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	http_utils "coffee-tracker-backend/internal/infrastructure/http"
+	"coffee-tracker-backend/internal/infrastructure/http/models"
+	"coffee-tracker-backend/internal/usecases"
+
+	"github.com/google/uuid"
+)
+
+type GoalHandler struct {
+	getAllUC   *usecases.GetGoalsUseCase
+	setUC      *usecases.SetGoalUseCase
+	deleteUC   *usecases.DeleteGoalUseCase
+	progressUC *usecases.GetGoalProgressUseCase
+}
+
+func NewGoalHandler(
+	getAllUC *usecases.GetGoalsUseCase,
+	setUC *usecases.SetGoalUseCase,
+	deleteUC *usecases.DeleteGoalUseCase,
+	progressUC *usecases.GetGoalProgressUseCase,
+) *GoalHandler {
+	return &GoalHandler{
+		getAllUC:   getAllUC,
+		setUC:      setUC,
+		deleteUC:   deleteUC,
+		progressUC: progressUC,
+	}
+}
+
+// GET /goals
+func (h *GoalHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	goals, err := h.getAllUC.Execute(r.Context(), userID)
+	if err != nil {
+		http_utils.WriteError(w, http.StatusInternalServerError, "Failed to load goals")
+		return
+	}
+
+	http_utils.WriteJSON(w, http.StatusOK, map[string]any{
+		"goals": goals,
+	})
+}
+
+// PUT /goals
+func (h *GoalHandler) Set(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.SetGoalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http_utils.WriteError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	goal, err := h.setUC.Execute(r.Context(), userID, &req)
+	if err != nil {
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "Invalid goal type or target")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to save goal")
+		}
+		return
+	}
+
+	http_utils.WriteJSON(w, http.StatusOK, goal)
+}
+
+// DELETE /goals/{id}
+func (h *GoalHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	goalID, err := uuid.Parse(http_utils.GetPathParam(r, "id"))
+	if err != nil {
+		http_utils.WriteError(w, http.StatusBadRequest, "Invalid goal ID format")
+		return
+	}
+
+	if err := h.deleteUC.Execute(r.Context(), userID, goalID); err != nil {
+		switch err {
+		case usecases.ErrNotFound:
+			http_utils.WriteError(w, http.StatusNotFound, err.Error())
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to delete goal")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET /goals/progress?tz=Europe/Lisbon
+func (h *GoalHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	progress, err := h.progressUC.Execute(r.Context(), userID, r.URL.Query().Get("tz"))
+	if err != nil {
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "Invalid tz (IANA name)")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to compute goal progress")
+		}
+		return
+	}
+
+	http_utils.WriteJSON(w, http.StatusOK, map[string]any{
+		"progress": progress,
+	})
+}
@@ -0,0 +1,160 @@
+// file: internal/infrastructure/http/handlers/import_handler.go
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	http_utils "coffee-tracker-backend/internal/infrastructure/http"
+	"coffee-tracker-backend/internal/infrastructure/http/models"
+	"coffee-tracker-backend/internal/usecases"
+)
+
+const (
+	// maxImportBytes bounds the size of an import upload
+	maxImportBytes = 10 << 20 // 10 MB
+	// importTimeout replaces the server's read and write timeouts for an import, which are too
+	// short to upload and insert the largest one
+	importTimeout = 2 * time.Minute
+)
+
+type ImportHandler struct {
+	importUC *usecases.ImportCoffeeEntriesUseCase
+}
+
+func NewImportHandler(importUC *usecases.ImportCoffeeEntriesUseCase) *ImportHandler {
+	return &ImportHandler{importUC: importUC}
+}
+
+// POST /entries/import?format=csv&dry_run=true&language=en&tzOffset=180
+// format is csv, json, apple_health (export.xml) or ical (.ics); by default it is taken from the
+// file extension or the Content-Type.
+// The file is either a multipart "file" field (with an optional "mapping" field) or the raw body.
+// mapping is a JSON object of entry field -> source column, e.g. {"timestamp": "Date", "type": "Drink"}.
+func (h *ImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	deadline := time.Now().Add(importTimeout)
+	if err := rc.SetReadDeadline(deadline); err != nil {
+		log.Printf("import for user %s keeps the read timeout, a large upload may be cut short: %v", userID, err)
+	}
+	if err := rc.SetWriteDeadline(deadline); err != nil {
+		log.Printf("import for user %s keeps the write timeout, a large import may be cut short: %v", userID, err)
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	q := r.URL.Query()
+	req := &models.ImportCoffeeEntriesRequest{
+		Format:   q.Get("format"),
+		Language: q.Get("language"),
+	}
+
+	var err error
+	if req.TzOffset, err = optionalInt(q.Get("tzOffset")); err != nil {
+		http_utils.WriteError(w, http.StatusBadRequest, "Invalid query parameters", err.Error())
+		return
+	}
+	dryRun, err := optionalBool(q.Get("dry_run"))
+	if err != nil {
+		http_utils.WriteError(w, http.StatusBadRequest, "Invalid query parameters", err.Error())
+		return
+	}
+	req.DryRun = dryRun != nil && *dryRun
+
+	mapping := q.Get("mapping")
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(maxImportBytes); err != nil {
+			http_utils.WriteError(w, http.StatusBadRequest, "Invalid or too large file", err.Error())
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http_utils.WriteError(w, http.StatusBadRequest, "Failed to read file", err.Error())
+			return
+		}
+		defer file.Close()
+
+		req.Data = file
+		if req.Format == "" {
+			req.Format = importFormatOfExt(path.Ext(header.Filename))
+		}
+		if m := r.FormValue("mapping"); m != "" {
+			mapping = m
+		}
+	} else {
+		req.Data = r.Body
+		if req.Format == "" {
+			req.Format = importFormatOf(mediaType)
+		}
+	}
+
+	if mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &req.Mapping); err != nil {
+			http_utils.WriteError(w, http.StatusBadRequest, "Invalid mapping", err.Error())
+			return
+		}
+	}
+
+	report, err := h.importUC.Execute(r.Context(), userID, req)
+	if err != nil {
+		switch {
+		case report != nil:
+			// Nothing was imported; the report points at the invalid rows
+			http_utils.WriteJSON(w, http.StatusUnprocessableEntity, map[string]any{
+				"error":   "Import rejected: " + err.Error(),
+				"status":  http.StatusUnprocessableEntity,
+				"success": false,
+				"report":  report,
+			})
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to import entries")
+		}
+		return
+	}
+
+	status := http.StatusCreated
+	if req.DryRun {
+		status = http.StatusOK
+	}
+	http_utils.WriteJSON(w, status, report)
+}
+
+// importFormatOf maps the Content-Type of a raw upload to an import format
+func importFormatOf(mediaType string) string {
+	switch mediaType {
+	case "text/csv":
+		return "csv"
+	case "application/json":
+		return "json"
+	case "application/xml", "text/xml":
+		return "apple_health"
+	case "text/calendar":
+		return "ical"
+	}
+	return ""
+}
+
+// importFormatOfExt maps the extension of an uploaded file name to an import format
+func importFormatOfExt(ext string) string {
+	switch strings.ToLower(ext) {
+	case ".csv":
+		return "csv"
+	case ".json":
+		return "json"
+	case ".xml":
+		return "apple_health"
+	case ".ics":
+		return "ical"
+	}
+	return ""
+}
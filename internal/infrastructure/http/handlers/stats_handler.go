@@ -0,0 +1,200 @@
+// file: internal/infrastructure/http/handlers/stats_handler.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+	http_utils "coffee-tracker-backend/internal/infrastructure/http"
+	"coffee-tracker-backend/internal/infrastructure/utils"
+	"coffee-tracker-backend/internal/usecases"
+)
+
+type StatsHandler struct {
+	dailyCaffeineUC *usecases.GetDailyCaffeineUseCase
+	caffeineCurveUC *usecases.GetCaffeineCurveUseCase
+	seriesUC        *usecases.GetCoffeeSeriesUseCase
+	heatmapUC       *usecases.GetConsumptionHeatmapUseCase
+	spendingUC      *usecases.GetSpendingReportUseCase
+}
+
+func NewStatsHandler(
+	dailyCaffeineUC *usecases.GetDailyCaffeineUseCase,
+	caffeineCurveUC *usecases.GetCaffeineCurveUseCase,
+	seriesUC *usecases.GetCoffeeSeriesUseCase,
+	heatmapUC *usecases.GetConsumptionHeatmapUseCase,
+	spendingUC *usecases.GetSpendingReportUseCase,
+) *StatsHandler {
+	return &StatsHandler{
+		dailyCaffeineUC: dailyCaffeineUC,
+		caffeineCurveUC: caffeineCurveUC,
+		seriesUC:        seriesUC,
+		heatmapUC:       heatmapUC,
+		spendingUC:      spendingUC,
+	}
+}
+
+// GET /stats/caffeine?date=2025-08-21&tzOffset=180
+func (h *StatsHandler) GetDailyCaffeine(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	tzOffset, ok := parseTzOffset(w, r)
+	if !ok {
+		return
+	}
+
+	daily, err := h.dailyCaffeineUC.Execute(r.Context(), userID, r.URL.Query().Get("date"), tzOffset)
+	if err != nil {
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "Invalid date, expected YYYY-MM-DD")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to get caffeine total")
+		}
+		return
+	}
+
+	http_utils.WriteJSON(w, http.StatusOK, daily)
+}
+
+// GET /stats/caffeine-curve?at=2025-08-21T21:00:00Z&step=30
+func (h *StatsHandler) GetCaffeineCurve(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	at := utils.NowUTC()
+	if atStr := r.URL.Query().Get("at"); atStr != "" {
+		parsed, err := time.Parse(time.RFC3339, atStr)
+		if err != nil {
+			http_utils.WriteError(w, http.StatusBadRequest, "'at' must be an RFC3339 timestamp")
+			return
+		}
+		at = parsed
+	}
+
+	var step int
+	if stepStr := r.URL.Query().Get("step"); stepStr != "" {
+		parsed, err := strconv.Atoi(stepStr)
+		if err != nil {
+			http_utils.WriteError(w, http.StatusBadRequest, "'step' must be a number of minutes")
+			return
+		}
+		step = parsed
+	}
+
+	curve, err := h.caffeineCurveUC.Execute(r.Context(), userID, at, step)
+	if err != nil {
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "'step' must be between 5 and 240 minutes")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to compute caffeine curve")
+		}
+		return
+	}
+
+	http_utils.WriteJSON(w, http.StatusOK, curve)
+}
+
+// GET /stats/series?from=2025-08-01&to=2025-08-31&bucket=day|week|month&tz=Europe/Lisbon
+func (h *StatsHandler) GetSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	series, err := h.seriesUC.Execute(
+		r.Context(),
+		userID,
+		query.Get("from"),
+		query.Get("to"),
+		entities.StatsBucket(query.Get("bucket")),
+		query.Get("tz"),
+	)
+	if err != nil {
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "Invalid from/to (YYYY-MM-DD), bucket (day|week|month) or tz (IANA name), or range too large")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to get series")
+		}
+		return
+	}
+
+	http_utils.WriteJSON(w, http.StatusOK, series)
+}
+
+// GET /stats/heatmap?from=2025-06-01&to=2025-08-31&tz=Europe/Lisbon
+func (h *StatsHandler) GetHeatmap(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	heatmap, err := h.heatmapUC.Execute(r.Context(), userID, query.Get("from"), query.Get("to"), query.Get("tz"))
+	if err != nil {
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "Invalid from/to (YYYY-MM-DD) or tz (IANA name)")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to get heatmap")
+		}
+		return
+	}
+
+	http_utils.WriteJSON(w, http.StatusOK, heatmap)
+}
+
+// GET /stats/spending?from=2025-01-01&to=2025-08-31&bucket=day|week|month&tz=Europe/Lisbon
+func (h *StatsHandler) GetSpending(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	report, err := h.spendingUC.Execute(
+		r.Context(),
+		userID,
+		query.Get("from"),
+		query.Get("to"),
+		entities.StatsBucket(query.Get("bucket")),
+		query.Get("tz"),
+	)
+	if err != nil {
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "Invalid from/to (YYYY-MM-DD), bucket (day|week|month) or tz (IANA name), or range too large")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to get spending")
+		}
+		return
+	}
+
+	http_utils.WriteJSON(w, http.StatusOK, report)
+}
+
+// parseTzOffset reads the optional "tzOffset" query param (minutes east of UTC).
+// Writes a 400 and returns false when it is malformed.
+func parseTzOffset(w http.ResponseWriter, r *http.Request) (*int, bool) {
+	tzOffsetStr := r.URL.Query().Get("tzOffset")
+	if tzOffsetStr == "" {
+		return nil, true
+	}
+
+	tzOffset, err := strconv.Atoi(tzOffsetStr)
+	if err != nil {
+		http_utils.WriteError(w, http.StatusBadRequest, "'tzOffset' must be a number of minutes")
+		return nil, false
+	}
+	return &tzOffset, true
+}
@@ -0,0 +1,85 @@
+// file: internal/infrastructure/http/handlers/sync_handler.go
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	http_utils "coffee-tracker-backend/internal/infrastructure/http"
+	"coffee-tracker-backend/internal/infrastructure/http/models"
+	"coffee-tracker-backend/internal/usecases"
+)
+
+type SyncHandler struct {
+	getChangesUC *usecases.GetSyncChangesUseCase
+	applyUC      *usecases.ApplySyncMutationsUseCase
+}
+
+func NewSyncHandler(
+	getChangesUC *usecases.GetSyncChangesUseCase,
+	applyUC *usecases.ApplySyncMutationsUseCase,
+) *SyncHandler {
+	return &SyncHandler{
+		getChangesUC: getChangesUC,
+		applyUC:      applyUC,
+	}
+}
+
+// GET /sync?since=<token>&limit=200
+func (h *SyncHandler) GetChanges(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := optionalInt(r.URL.Query().Get("limit"))
+	if err != nil {
+		http_utils.WriteError(w, http.StatusBadRequest, "'limit' must be a number")
+		return
+	}
+	if limit == nil {
+		limit = new(int)
+	}
+
+	changes, err := h.getChangesUC.Execute(r.Context(), userID, r.URL.Query().Get("since"), *limit)
+	if err != nil {
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "Invalid 'since' token or 'limit' (max 1000)")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to get changes")
+		}
+		return
+	}
+
+	http_utils.WriteJSON(w, http.StatusOK, changes)
+}
+
+// POST /sync
+func (h *SyncHandler) Apply(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.SyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http_utils.WriteError(w, http.StatusBadRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	results, err := h.applyUC.Execute(r.Context(), userID, &req)
+	if err != nil {
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "Expected 1 to 500 mutations")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to apply mutations")
+		}
+		return
+	}
+
+	http_utils.WriteJSON(w, http.StatusOK, map[string]any{
+		"results": results,
+	})
+}
@@ -0,0 +1,89 @@
+// file: internal/infrastructure/http/handlers/trash_handler.go
+package handlers
+
+import (
+	"net/http"
+
+	http_utils "coffee-tracker-backend/internal/infrastructure/http"
+	"coffee-tracker-backend/internal/usecases"
+)
+
+type TrashHandler struct {
+	getTrashUC *usecases.GetTrashUseCase
+	restoreUC  *usecases.RestoreCoffeeEntryUseCase
+}
+
+func NewTrashHandler(
+	getTrashUC *usecases.GetTrashUseCase,
+	restoreUC *usecases.RestoreCoffeeEntryUseCase,
+) *TrashHandler {
+	return &TrashHandler{
+		getTrashUC: getTrashUC,
+		restoreUC:  restoreUC,
+	}
+}
+
+// GET /entries/trash?limit=50&offset=0
+func (h *TrashHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	limit, err := optionalInt(r.URL.Query().Get("limit"))
+	if err != nil {
+		http_utils.WriteError(w, http.StatusBadRequest, "'limit' must be a number")
+		return
+	}
+	offset, err := optionalInt(r.URL.Query().Get("offset"))
+	if err != nil {
+		http_utils.WriteError(w, http.StatusBadRequest, "'offset' must be a number")
+		return
+	}
+	if limit == nil {
+		limit = new(int)
+	}
+	if offset == nil {
+		offset = new(int)
+	}
+
+	entries, err := h.getTrashUC.Execute(r.Context(), userID, *limit, *offset)
+	if err != nil {
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "Invalid limit or offset")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to get deleted entries")
+		}
+		return
+	}
+
+	http_utils.WriteJSON(w, http.StatusOK, entries)
+}
+
+// POST /entries/{id}/restore
+func (h *TrashHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	userID, ok := http_utils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	entryID, err := http_utils.GetEntryIDByRouteOrAbort(r, w)
+	if err != nil {
+		http_utils.WriteError(w, http.StatusBadRequest, usecases.ErrInvalidInput.Error())
+		return
+	}
+
+	entry, err := h.restoreUC.Execute(r.Context(), userID, entryID)
+	if err != nil {
+		switch err {
+		case usecases.ErrNotFound:
+			http_utils.WriteError(w, http.StatusNotFound, "Entry is not in the trash")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to restore entry")
+		}
+		return
+	}
+
+	http_utils.WriteJSON(w, http.StatusOK, entry)
+}
@@ -8,6 +8,8 @@ import (
 	httpUtils "coffee-tracker-backend/internal/infrastructure/http"
 	"coffee-tracker-backend/internal/infrastructure/http/models"
 	"coffee-tracker-backend/internal/usecases"
+
+	"github.com/google/uuid"
 )
 
 type UserHandler struct {
@@ -15,6 +17,8 @@ type UserHandler struct {
 	updateProfileUC *usecases.UpdateUserProfileUseCase
 	uploadImageUC   *usecases.UploadUserProfileImageUseCase
 	deleteImageUC   *usecases.DeleteUserProfileImageUseCase
+	exportDataUC    *usecases.ExportUserDataUseCase
+	deleteAccountUC *usecases.DeleteAccountUseCase
 }
 
 func NewUserHandler(
@@ -22,12 +26,16 @@ func NewUserHandler(
 	updateProfileUC *usecases.UpdateUserProfileUseCase,
 	uploadImageUC *usecases.UploadUserProfileImageUseCase,
 	deleteImageUC *usecases.DeleteUserProfileImageUseCase,
+	exportDataUC *usecases.ExportUserDataUseCase,
+	deleteAccountUC *usecases.DeleteAccountUseCase,
 ) *UserHandler {
 	return &UserHandler{
 		getProfileUC:    getProfileUC,
 		updateProfileUC: updateProfileUC,
 		uploadImageUC:   uploadImageUC,
 		deleteImageUC:   deleteImageUC,
+		exportDataUC:    exportDataUC,
+		deleteAccountUC: deleteAccountUC,
 	}
 }
 
@@ -132,3 +140,75 @@ func (h *UserHandler) DeleteProfileImage(w http.ResponseWriter, r *http.Request)
 
 	httpUtils.WriteJSON(w, http.StatusOK, map[string]string{"message": "Profile image deleted"})
 }
+
+// POST /user/export
+// Starts building an archive of all the user's data; poll GET /user/export/{id} for the download link
+func (h *UserHandler) StartDataExport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := httpUtils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	export, err := h.exportDataUC.Start(r.Context(), userID)
+	if err != nil {
+		switch err {
+		case usecases.ErrConflict:
+			httpUtils.WriteError(w, http.StatusConflict, "An export is already being prepared")
+		default:
+			httpUtils.WriteError(w, http.StatusInternalServerError, "Failed to start export")
+		}
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/user/export/"+export.ID.String())
+	httpUtils.WriteJSON(w, http.StatusAccepted, export)
+}
+
+// GET /user/export/{id}
+func (h *UserHandler) GetDataExport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := httpUtils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	exportID, err := uuid.Parse(httpUtils.GetPathParam(r, "id"))
+	if err != nil {
+		httpUtils.WriteError(w, http.StatusBadRequest, "Invalid export ID format")
+		return
+	}
+
+	export, err := h.exportDataUC.Get(r.Context(), userID, exportID)
+	if err != nil {
+		switch err {
+		case usecases.ErrNotFound:
+			httpUtils.WriteError(w, http.StatusNotFound, "Export not found")
+		default:
+			httpUtils.WriteError(w, http.StatusInternalServerError, "Failed to load export")
+		}
+		return
+	}
+
+	httpUtils.WriteJSON(w, http.StatusOK, export)
+}
+
+// DELETE /user
+// Deletes the account and signs it out everywhere; logging in again during the grace period restores it
+func (h *UserHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	httpUtils.LogRequest(r)
+
+	userID, ok := httpUtils.GetUserIDOrAbort(w, r)
+	if !ok {
+		return
+	}
+
+	purgeAt, err := h.deleteAccountUC.Execute(r.Context(), userID)
+	if err != nil {
+		httpUtils.WriteError(w, http.StatusInternalServerError, "Failed to delete account")
+		return
+	}
+
+	httpUtils.WriteJSON(w, http.StatusAccepted, models.DeleteAccountResponse{
+		Message:             "Account scheduled for deletion",
+		DeletionScheduledAt: purgeAt,
+	})
+}
@@ -66,7 +66,12 @@ func (h *UserSettingsHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.updateUC.Execute(r.Context(), userID, setting, body.Value); err != nil {
-		http_utils.WriteError(w, http.StatusInternalServerError, "Failed to update setting", err.Error())
+		switch err {
+		case usecases.ErrInvalidInput:
+			http_utils.WriteError(w, http.StatusBadRequest, "Invalid value for setting")
+		default:
+			http_utils.WriteError(w, http.StatusInternalServerError, "Failed to update setting", err.Error())
+		}
 		return
 	}
 
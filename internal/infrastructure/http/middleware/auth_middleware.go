@@ -31,6 +31,11 @@ func AuthMiddleware(tokenService auth.TokenService) func(http.Handler) http.Hand
 				http.Error(w, "refresh token cannot be used for API access", http.StatusUnauthorized)
 				return
 			}
+			// Same for any other token type (e.g. confirmation tokens)
+			if !tokenService.IsAccessToken(claims) {
+				http.Error(w, "only access tokens can be used for API access", http.StatusUnauthorized)
+				return
+			}
 
 			userID, err := tokenService.ExtractUserIDFromToken(tokenString)
 			if err != nil {
@@ -0,0 +1,72 @@
+// file: internal/infrastructure/http/middleware/client_ip_middleware.go
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"coffee-tracker-backend/internal/contextkeys"
+)
+
+// ClientIP resolves the client address once per request, for http_utils.GetUserIpAddress.
+// With a clientIPHeader (e.g. Fly-Client-IP), the address set in it by the platform's proxy is used;
+// that proxy must overwrite the header on every request. Otherwise X-Forwarded-For is only believed
+// when the peer is one of the trusted proxies; the client is then the right-most hop that is not a
+// trusted proxy, since anything left of it may be forged.
+// Must run before the middlewares reading the client address (e.g. RequestLogger).
+func ClientIP(trustedProxies []netip.Prefix, clientIPHeader string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientIP := resolveClientIP(r, trustedProxies)
+			if clientIPHeader != "" {
+				if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get(clientIPHeader))); err == nil {
+					clientIP = addr.Unmap().String()
+				}
+			}
+			ctx := context.WithValue(r.Context(), contextkeys.ClientIPKey, clientIP)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func resolveClientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	peer, err := netip.ParseAddr(host)
+	if err != nil || !isTrustedProxy(peer, trustedProxies) {
+		return host
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	client := peer
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// Garbage is not from a proxy of ours: the last proxy seen is as far as we can trust
+			break
+		}
+		client = hop
+		if !isTrustedProxy(hop, trustedProxies) {
+			break
+		}
+	}
+	return client.Unmap().String()
+}
+
+func isTrustedProxy(addr netip.Addr, trustedProxies []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -56,3 +56,8 @@ func (lrw *loggingResponseWriter) Write(b []byte) (int, error) {
 	lrw.bytesWritten += n
 	return n, err
 }
+
+// Unwrap lets http.ResponseController reach the underlying writer (Flush, SetWriteDeadline)
+func (lrw *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return lrw.ResponseWriter
+}
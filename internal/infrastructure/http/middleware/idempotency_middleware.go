@@ -0,0 +1,162 @@
+// file: internal/infrastructure/http/middleware/idempotency_middleware.go
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+	http_utils "coffee-tracker-backend/internal/infrastructure/http"
+	"coffee-tracker-backend/internal/infrastructure/utils"
+	"coffee-tracker-backend/internal/repositories"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	// idempotencyStaleAfter is how long an in-progress key blocks retries before it is
+	// considered abandoned (the server died mid-request); well above the longest request, an import (2 min).
+	idempotencyStaleAfter = 5 * time.Minute
+	// maxIdempotentBodyBytes caps the body buffered for hashing; the largest route body is an import (10 MB)
+	maxIdempotentBodyBytes = 10 << 20
+)
+
+// Idempotency makes mutating requests carrying an Idempotency-Key header safe to retry.
+// The first response per user and key is stored for ttl and replayed to later requests with the
+// same key; reusing a key with a different method, path, query or body is rejected with 422, and a
+// retry that arrives while the first request is still running gets 409. Server errors (5xx) are
+// not stored, so the request can be retried. Must run after AuthMiddleware.
+func Idempotency(repo repositories.IdempotencyRepository, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || !isMutatingMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				http_utils.WriteError(w, http.StatusBadRequest, "Idempotency-Key is too long")
+				return
+			}
+
+			userID, ok := http_utils.GetUserIDOrAbort(w, r)
+			if !ok {
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodyBytes))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					http_utils.WriteError(w, http.StatusRequestEntityTooLarge, "Request body is too large")
+					return
+				}
+				http_utils.WriteError(w, http.StatusBadRequest, "Failed to read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			now := utils.NowUTC()
+			record := &entities.IdempotencyRecord{
+				UserID:      userID,
+				Key:         key,
+				RequestHash: requestHash(r, body),
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(ttl),
+			}
+
+			existing, reserved, err := repo.Reserve(r.Context(), record, now.Add(-idempotencyStaleAfter))
+			if err != nil {
+				log.Printf("idempotency: reserve key failed: %v", err)
+				http_utils.WriteError(w, http.StatusInternalServerError, "Failed to process Idempotency-Key")
+				return
+			}
+
+			if !reserved {
+				switch {
+				case existing.RequestHash != record.RequestHash:
+					http_utils.WriteError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+				case !existing.IsCompleted():
+					http_utils.WriteError(w, http.StatusConflict, "A request with this Idempotency-Key is still in progress")
+				default:
+					if existing.ContentType != "" {
+						w.Header().Set("Content-Type", existing.ContentType)
+					}
+					w.Header().Set(IdempotentReplayedHeader, "true")
+					w.WriteHeader(*existing.StatusCode)
+					w.Write(existing.ResponseBody)
+				}
+				return
+			}
+
+			rec := &recordingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			// The request context may be cancelled by now (client gone), the outcome must still be stored
+			ctx := context.WithoutCancel(r.Context())
+			if rec.statusCode >= http.StatusInternalServerError {
+				err = repo.Release(ctx, userID, key)
+			} else {
+				err = repo.Complete(ctx, userID, key, rec.statusCode, rec.Header().Get("Content-Type"), rec.body.Bytes())
+			}
+			if err != nil {
+				log.Printf("idempotency: storing outcome for key failed: %v", err)
+			}
+		})
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// requestHash fingerprints a request so a key can't be replayed against a different one.
+// The query is hashed with its parameters sorted, so reordering them is the same request.
+// Multipart boundaries are random per attempt (e.g. avatar uploads), so they are left out.
+func requestHash(r *http.Request, body []byte) string {
+	if mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil &&
+		strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "" {
+		body = bytes.ReplaceAll(body, []byte(params["boundary"]), nil)
+	}
+
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.Path+"?"+r.URL.Query().Encode()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingResponseWriter passes the response through while keeping a copy of it
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *recordingResponseWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingResponseWriter) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the wrapped writer to http.ResponseController
+func (rw *recordingResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,205 @@
+// file: internal/infrastructure/http/middleware/idempotency_middleware_test.go
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"coffee-tracker-backend/internal/contextkeys"
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// memoryIdempotencyRepo keeps records in a map; expiry and staleness are not needed by these tests
+type memoryIdempotencyRepo struct {
+	repositories.IdempotencyRepository
+	mu      sync.Mutex
+	records map[string]*entities.IdempotencyRecord
+}
+
+func newMemoryIdempotencyRepo() *memoryIdempotencyRepo {
+	return &memoryIdempotencyRepo{records: map[string]*entities.IdempotencyRecord{}}
+}
+
+func (m *memoryIdempotencyRepo) Reserve(ctx context.Context, record *entities.IdempotencyRecord, staleBefore time.Time) (*entities.IdempotencyRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := record.UserID.String() + "/" + record.Key
+	if existing, ok := m.records[id]; ok {
+		stored := *existing
+		return &stored, false, nil
+	}
+	stored := *record
+	m.records[id] = &stored
+	return nil, true, nil
+}
+
+func (m *memoryIdempotencyRepo) Complete(ctx context.Context, userID uuid.UUID, key string, statusCode int, contentType string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record := m.records[userID.String()+"/"+key]
+	record.StatusCode, record.ContentType, record.ResponseBody = &statusCode, contentType, body
+	return nil
+}
+
+func (m *memoryIdempotencyRepo) Release(ctx context.Context, userID uuid.UUID, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, userID.String()+"/"+key)
+	return nil
+}
+
+// newIdempotentHandler returns the middleware around a handler that counts its calls and echoes them
+func newIdempotentHandler(repo repositories.IdempotencyRepository, status int) (http.Handler, *int) {
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"call":%d}`, calls)
+	})
+	return Idempotency(repo, time.Hour)(next), &calls
+}
+
+func idempotentRequest(userID uuid.UUID, key, target, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		r.Header.Set(IdempotencyKeyHeader, key)
+	}
+	return r.WithContext(context.WithValue(r.Context(), contextkeys.UserIDKey, userID))
+}
+
+func serve(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestIdempotencyReplaysStoredResponse(t *testing.T) {
+	h, calls := newIdempotentHandler(newMemoryIdempotencyRepo(), http.StatusCreated)
+	userID := uuid.New()
+
+	first := serve(h, idempotentRequest(userID, "key-1", "/api/v1/entries", `{"size":"M"}`))
+	second := serve(h, idempotentRequest(userID, "key-1", "/api/v1/entries", `{"size":"M"}`))
+
+	if *calls != 1 {
+		t.Fatalf("handler called %d times, want 1", *calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %q, want %d %q", second.Code, second.Body.String(), first.Code, first.Body.String())
+	}
+	if second.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("replay is missing the %s header", IdempotentReplayedHeader)
+	}
+	if second.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("replay Content-Type = %q", second.Header().Get("Content-Type"))
+	}
+}
+
+func TestIdempotencyRejectsReuseForDifferentRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		body   string
+	}{
+		{name: "different body", target: "/api/v1/entries?mode=merge&dry_run=false", body: `{"size":"L"}`},
+		{name: "different query", target: "/api/v1/entries?mode=replace&dry_run=false", body: `{"size":"M"}`},
+		{name: "different path", target: "/api/v1/entries:batch?mode=merge&dry_run=false", body: `{"size":"M"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, calls := newIdempotentHandler(newMemoryIdempotencyRepo(), http.StatusOK)
+			userID := uuid.New()
+
+			serve(h, idempotentRequest(userID, "key-1", "/api/v1/entries?mode=merge&dry_run=false", `{"size":"M"}`))
+			w := serve(h, idempotentRequest(userID, "key-1", tt.target, tt.body))
+
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+			}
+			if *calls != 1 {
+				t.Errorf("handler called %d times, want 1", *calls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyIgnoresQueryParameterOrder(t *testing.T) {
+	h, calls := newIdempotentHandler(newMemoryIdempotencyRepo(), http.StatusOK)
+	userID := uuid.New()
+
+	serve(h, idempotentRequest(userID, "key-1", "/api/v1/entries?mode=merge&dry_run=false", `{}`))
+	w := serve(h, idempotentRequest(userID, "key-1", "/api/v1/entries?dry_run=false&mode=merge", `{}`))
+
+	if w.Code != http.StatusOK || w.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("status = %d, replayed = %q; want a replay", w.Code, w.Header().Get(IdempotentReplayedHeader))
+	}
+	if *calls != 1 {
+		t.Errorf("handler called %d times, want 1", *calls)
+	}
+}
+
+func TestIdempotencyKeysAreScopedPerUser(t *testing.T) {
+	h, calls := newIdempotentHandler(newMemoryIdempotencyRepo(), http.StatusOK)
+
+	serve(h, idempotentRequest(uuid.New(), "key-1", "/api/v1/entries", `{}`))
+	w := serve(h, idempotentRequest(uuid.New(), "key-1", "/api/v1/entries", `{}`))
+
+	if w.Header().Get(IdempotentReplayedHeader) != "" || *calls != 2 {
+		t.Errorf("another user's key was replayed (calls = %d)", *calls)
+	}
+}
+
+func TestIdempotencyDoesNotStoreServerErrors(t *testing.T) {
+	h, calls := newIdempotentHandler(newMemoryIdempotencyRepo(), http.StatusInternalServerError)
+	userID := uuid.New()
+
+	serve(h, idempotentRequest(userID, "key-1", "/api/v1/entries", `{}`))
+	serve(h, idempotentRequest(userID, "key-1", "/api/v1/entries", `{}`))
+
+	if *calls != 2 {
+		t.Errorf("handler called %d times, want the retry to run again", *calls)
+	}
+}
+
+func TestIdempotencyConflictWhileInProgress(t *testing.T) {
+	repo := newMemoryIdempotencyRepo()
+	userID := uuid.New()
+	var inner *httptest.ResponseRecorder
+
+	var h http.Handler
+	h = Idempotency(repo, time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if inner == nil {
+			inner = serve(h, idempotentRequest(userID, "key-1", "/api/v1/entries", `{}`))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve(h, idempotentRequest(userID, "key-1", "/api/v1/entries", `{}`))
+
+	if inner.Code != http.StatusConflict {
+		t.Errorf("concurrent retry status = %d, want %d", inner.Code, http.StatusConflict)
+	}
+}
+
+func TestIdempotencyWithoutKeyPassesThrough(t *testing.T) {
+	h, calls := newIdempotentHandler(newMemoryIdempotencyRepo(), http.StatusOK)
+	userID := uuid.New()
+
+	serve(h, idempotentRequest(userID, "", "/api/v1/entries", `{}`))
+	serve(h, idempotentRequest(userID, "", "/api/v1/entries", `{}`))
+
+	if *calls != 2 {
+		t.Errorf("handler called %d times, want 2", *calls)
+	}
+}
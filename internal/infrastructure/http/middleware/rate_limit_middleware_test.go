@@ -0,0 +1,98 @@
+// file: internal/infrastructure/http/middleware/rate_limit_middleware_test.go
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestByIPBehindClientIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		flyClientIP  string
+		want         string
+	}{
+		{
+			name:       "direct client",
+			remoteAddr: "203.0.113.7:5000",
+			want:       "203.0.113.7",
+		},
+		{
+			name:         "forged X-Forwarded-For from an untrusted peer is ignored",
+			remoteAddr:   "203.0.113.7:5000",
+			forwardedFor: []string{"198.51.100.1"},
+			want:         "203.0.113.7",
+		},
+		{
+			name:         "trusted proxy: the right-most untrusted hop",
+			remoteAddr:   "10.0.0.2:5000",
+			forwardedFor: []string{"198.51.100.1, 203.0.113.7, 10.0.0.3"},
+			want:         "203.0.113.7",
+		},
+		{
+			name:         "trusted proxy: hops split over several headers",
+			remoteAddr:   "10.0.0.2:5000",
+			forwardedFor: []string{"198.51.100.1", "203.0.113.7"},
+			want:         "203.0.113.7",
+		},
+		{
+			name:         "trusted proxy: garbage stops at the last proxy",
+			remoteAddr:   "10.0.0.2:5000",
+			forwardedFor: []string{"not-an-ip, 10.0.0.3"},
+			want:         "10.0.0.3",
+		},
+		{
+			name:       "trusted proxy without X-Forwarded-For",
+			remoteAddr: "10.0.0.2:5000",
+			want:       "10.0.0.2",
+		},
+		{
+			name:         "client IP header set by the platform proxy",
+			remoteAddr:   "172.16.0.5:5000",
+			forwardedFor: []string{"198.51.100.1"},
+			flyClientIP:  "203.0.113.7",
+			want:         "203.0.113.7",
+		},
+		{
+			name:        "unparsable client IP header is ignored",
+			remoteAddr:  "172.16.0.5:5000",
+			flyClientIP: "nonsense",
+			want:        "172.16.0.5",
+		},
+		{
+			name:         "IPv6 client",
+			remoteAddr:   "10.0.0.2:5000",
+			forwardedFor: []string{"2001:db8::1"},
+			want:         "2001:db8::1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/verify-otp", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwardedFor {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			if tt.flyClientIP != "" {
+				r.Header.Set("Fly-Client-IP", tt.flyClientIP)
+			}
+
+			var got string
+			handler := ClientIP(trusted, "Fly-Client-IP")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ByIP(r)
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+
+			if got != tt.want {
+				t.Fatalf("ByIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -14,12 +14,38 @@ import (
 	"github.com/google/uuid"
 )
 
+// cachedUser is a user loaded by UserMiddleware, reused until expiresAt
+type cachedUser struct {
+	user      *entities.User
+	expiresAt time.Time
+}
 
+// UserCache keeps the users loaded by UserMiddleware for ttl
+type UserCache struct {
+	repo  repositories.UserRepository
+	ttl   time.Duration
+	mu    sync.RWMutex
+	users map[uuid.UUID]cachedUser
+}
 
-func UserMiddleware(repo repositories.UserRepository, ttl time.Duration) func(http.Handler) http.Handler {
-	cache := make(map[uuid.UUID]*entities.User)
-	var mu sync.RWMutex
+func NewUserCache(repo repositories.UserRepository, ttl time.Duration) *UserCache {
+	return &UserCache{
+		repo:  repo,
+		ttl:   ttl,
+		users: make(map[uuid.UUID]cachedUser),
+	}
+}
+
+// Evict forgets the user, so a status change (e.g. an account deletion) applies to their next request
+func (c *UserCache) Evict(userID uuid.UUID) {
+	c.mu.Lock()
+	delete(c.users, userID)
+	c.mu.Unlock()
+}
 
+// UserMiddleware loads the current user and rejects accounts that are not active.
+// Users are cached; changes made elsewhere apply within the cache ttl unless the user is evicted.
+func UserMiddleware(cache *UserCache) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			userID, ok := http_utils.GetUserIDOrAbort(w, r)
@@ -28,25 +54,23 @@ func UserMiddleware(repo repositories.UserRepository, ttl time.Duration) func(ht
 			var user *entities.User
 
 			// 1. Check cache
-			mu.RLock()
-			cachedUser, exists := cache[userID]
-			mu.RUnlock()
+			cache.mu.RLock()
+			cached, exists := cache.users[userID]
+			cache.mu.RUnlock()
 
-			if exists {
-				user = cachedUser
+			if exists && time.Now().Before(cached.expiresAt) {
+				user = cached.user
 			} else {
-				u, err := repo.GetByID(r.Context(), userID)
+				u, err := cache.repo.GetByID(r.Context(), userID)
 				if err != nil {
 					http.Error(w, "User not found", http.StatusUnauthorized)
 					return
 				}
 				user = u
 
-				mu.Lock()
-				cache[userID] = user
-				mu.Unlock()
-
-				// Optional: add expiration using TTL if needed
+				cache.mu.Lock()
+				cache.users[userID] = cachedUser{user: user, expiresAt: time.Now().Add(cache.ttl)}
+				cache.mu.Unlock()
 			}
 
 			// 4. Check status via entity method
@@ -8,6 +8,12 @@ type SendOtpRequest struct {
     Mobile string `json:"mobile" binding:"required"`
 }
 
+// RegisterRequest signs up a new mobile number; an OTP is sent to it to verify it.
+type RegisterRequest struct {
+    Mobile string `json:"mobile" binding:"required"`
+    Name   string `json:"name"`
+}
+
 // VerifyOtpRequest is used to verify a previously sent OTP.
 type VerifyOtpRequest struct {
     Mobile   string    `json:"mobile" binding:"required"`
@@ -0,0 +1,16 @@
+// file: internal/infrastructure/http/models/batch_dto.go
+package models
+
+import "github.com/google/uuid"
+
+// BatchRequest is a list of entry operations applied all-or-nothing, in order
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations"`
+}
+
+type BatchOperation struct {
+	Op     string                    `json:"op"`               // "create", "update" or "delete"
+	ID     uuid.UUID                 `json:"id,omitempty"`     // required for update and delete
+	Create *CreateCoffeeEntryRequest `json:"create,omitempty"` // required for create
+	Update *UpdateCoffeeEntryRequest `json:"update,omitempty"` // required for update
+}
@@ -2,8 +2,11 @@
 package models
 
 import (
+	"io"
 	"time"
 
+	"coffee-tracker-backend/internal/entities"
+
 	"github.com/google/uuid"
 )
 
@@ -15,6 +18,11 @@ type CreateCoffeeEntryRequest struct {
     Longitude *float64  `json:"longitude,omitempty"`
 	CoffeeType *int    	`json:"type,omitempty"`
 	Size *int    		`json:"size,omitempty"`
+	Caffeine *int 		`json:"caffeine_mg,omitempty"` // overrides the type/size estimate
+	TzOffset *int 		`json:"tz_offset,omitempty"`   // minutes east of UTC, defines the "day" for limit checks
+	Price *float64 		`json:"price,omitempty"`
+	Currency *string 	`json:"currency,omitempty"` // ISO 4217, required with price
+	Rating *int 		`json:"rating,omitempty"` // 1-5
 }
 
 type UpdateCoffeeEntryRequest struct {
@@ -23,4 +31,77 @@ type UpdateCoffeeEntryRequest struct {
     Timestamp time.Time `json:"timestamp"`
 	CoffeeType *int    	`json:"type,omitempty"`
 	Size *int    		`json:"size,omitempty"`
-}
\ No newline at end of file
+	Caffeine *int 		`json:"caffeine_mg,omitempty"` // overrides the type/size estimate
+	Price *float64 		`json:"price,omitempty"`
+	Currency *string 	`json:"currency,omitempty"` // ISO 4217, required with price
+	Rating *int 		`json:"rating,omitempty"` // 1-5
+}
+
+type CreateCoffeeEntryResponse struct {
+	*entities.CoffeeEntry
+	DailyLimit *entities.DailyLimitStatus `json:"daily_limit,omitempty"`
+}
+
+// ListCoffeeEntriesQuery holds the GET /entries query parameters. Dates are "2006-01-02" days in
+// the client's timezone; Date selects a single day and cannot be combined with From/To.
+type ListCoffeeEntriesQuery struct {
+	Date        string
+	From        string
+	To          string
+	TzOffset    *int // minutes east of UTC
+	CoffeeType  *int
+	Size        *int
+	HasNotes    *bool
+	HasLocation *bool
+	Sort        string
+	Cursor      *string // non-nil selects cursor pagination; "" is the first page
+	Limit       int
+	Offset      int
+}
+
+// CoffeeEntriesPage is the GET /entries response in cursor mode.
+// NextCursor is nil on the last page.
+type CoffeeEntriesPage struct {
+	Items      []*entities.CoffeeEntry `json:"items"`
+	NextCursor *string                 `json:"next_cursor"`
+}
+
+// ClearCoffeeEntriesQuery holds the DELETE /entries parameters. From and To are inclusive
+// "2006-01-02" days in the client's timezone; without them every entry is cleared.
+type ClearCoffeeEntriesQuery struct {
+	From              string
+	To                string
+	TzOffset          *int   // minutes east of UTC
+	ConfirmationToken string // from POST /entries/clear-confirmation for the same range
+}
+
+// ClearConfirmationResponse is returned by POST /entries/clear-confirmation
+type ClearConfirmationResponse struct {
+	ConfirmationToken string    `json:"confirmation_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+	Entries           int       `json:"entries"` // entries the clear would delete right now
+}
+
+// ClearCoffeeEntriesResponse is returned by DELETE /entries
+type ClearCoffeeEntriesResponse struct {
+	Deleted int64 `json:"deleted"` // entries moved to the trash
+}
+
+// ExportCoffeeEntriesQuery holds the GET /entries/export parameters. From and To are inclusive
+// "2006-01-02" days in the client's timezone; without them the whole history is exported.
+type ExportCoffeeEntriesQuery struct {
+	From     string
+	To       string
+	TzOffset *int   // minutes east of UTC
+	Language string // language of the type/size names, defaults to "en"
+}
+
+// ImportCoffeeEntriesRequest is a POST /entries/import upload
+type ImportCoffeeEntriesRequest struct {
+	Format   string            // a registered importer: "csv", "json", "apple_health" or "ical"
+	Data     io.Reader         // the uploaded file
+	Mapping  map[string]string // entry field -> source column; unmapped fields use their own name
+	DryRun   bool              // validate only, import nothing
+	Language string            // language of the type/size names, defaults to "en"
+	TzOffset *int              // minutes east of UTC, for timestamps without a zone
+}
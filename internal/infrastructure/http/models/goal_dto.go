@@ -0,0 +1,7 @@
+// file: internal/infrastructure/http/models/goal_dto.go
+package models
+
+type SetGoalRequest struct {
+	Type   string `json:"type"`   // max_cups_per_day | caffeine_free_days_per_week
+	Target int    `json:"target"` // cups per day, or caffeine-free days per week
+}
@@ -0,0 +1,34 @@
+// file: internal/infrastructure/http/models/sync_dto.go
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SyncRequest is a batch of mutations recorded by an offline client, applied in order
+type SyncRequest struct {
+	Mutations []SyncMutation `json:"mutations"`
+}
+
+type SyncMutation struct {
+	Op        string         `json:"op"`              // "upsert" or "delete"
+	ID        uuid.UUID      `json:"id"`              // client-generated
+	UpdatedAt time.Time      `json:"updated_at"`      // client time of the change; the newest change wins
+	Entry     *SyncEntryData `json:"entry,omitempty"` // required for upserts
+}
+
+// SyncEntryData is the full client state of an entry
+type SyncEntryData struct {
+	Notes      *string   `json:"notes,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+	Latitude   *float64  `json:"latitude,omitempty"`
+	Longitude  *float64  `json:"longitude,omitempty"`
+	CoffeeType *int      `json:"type,omitempty"`
+	Size       *int      `json:"size,omitempty"`
+	Caffeine   *int      `json:"caffeine_mg,omitempty"`
+	Price      *float64  `json:"price,omitempty"`
+	Currency   *string   `json:"currency,omitempty"`
+	Rating     *int      `json:"rating,omitempty"`
+}
@@ -2,6 +2,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -24,4 +26,9 @@ type UpdateUserProfileRequest struct {
 	//City    *string `json:"city,omitempty"`
 	//ZipCode *string `json:"zip_code,omitempty"`
 	// add more fields as needed
+}
+
+type DeleteAccountResponse struct {
+	Message             string    `json:"message"`
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"` // logging in before this restores the account
 }
\ No newline at end of file
@@ -8,8 +8,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -103,10 +105,20 @@ func logResponse(status int, data interface{}) {
 	)
 }
 
+// GetUserIpAddress returns the client address resolved by the ClientIP middleware,
+// otherwise the remote address without its port
 func GetUserIpAddress(r *http.Request) string {
-	clientIP := r.Header.Get("X-Forwarded-For")
-	if clientIP == "" {
-		clientIP = r.RemoteAddr
+	if clientIP, ok := r.Context().Value(contextkeys.ClientIPKey).(string); ok {
+		return clientIP
 	}
-	return clientIP
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// SetRetryAfter tells the client how long to wait before retrying, in whole seconds rounded up
+func SetRetryAfter(w http.ResponseWriter, wait time.Duration) {
+	seconds := int((wait + time.Second - 1) / time.Second)
+	w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
 }
\ No newline at end of file
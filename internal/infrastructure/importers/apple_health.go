@@ -0,0 +1,130 @@
+// file: internal/infrastructure/importers/apple_health.go
+package importers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/usecases"
+)
+
+// AppleHealthImporter implements EntryImporter
+var _ usecases.EntryImporter = (*AppleHealthImporter)(nil)
+
+// Columns of an Apple Health caffeine row
+const (
+	AppleHealthStartDate  = "startDate"   // RFC 3339
+	AppleHealthCaffeineMg = "caffeine_mg" // the record value converted to mg
+	AppleHealthSource     = "sourceName"  // the app or device that logged the record
+)
+
+const (
+	appleHealthCaffeineType = "HKQuantityTypeIdentifierDietaryCaffeine"
+	appleHealthDateLayout   = "2006-01-02 15:04:05 -0700"
+)
+
+// AppleHealthImporter reads the caffeine records of an Apple Health export.xml, uploaded unzipped;
+// export.zip itself is not accepted. Every other record type is skipped. Uploads are capped at
+// 10 MB, which a full Health export usually exceeds, so it must be trimmed to its caffeine records.
+type AppleHealthImporter struct {
+	MaxRecords int // stop with an error past this many caffeine records; 0 means no limit
+}
+
+func NewAppleHealthImporter(maxRecords int) *AppleHealthImporter {
+	return &AppleHealthImporter{MaxRecords: maxRecords}
+}
+
+func (i *AppleHealthImporter) DefaultMapping() map[string]string {
+	return map[string]string{
+		entities.ImportFieldTimestamp: AppleHealthStartDate,
+		entities.ImportFieldCaffeine:  AppleHealthCaffeineMg,
+	}
+}
+
+// Read streams the XML, so only the caffeine records are held in memory
+func (i *AppleHealthImporter) Read(data io.Reader) (map[string]bool, []map[string]string, error) {
+	decoder := xml.NewDecoder(data)
+	decoder.Strict = false // exports from some iOS versions carry a malformed DTD
+
+	var records []map[string]string
+	sawHealthData := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid Apple Health export: %v", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local == "HealthData" {
+			sawHealthData = true
+			continue
+		}
+		if start.Name.Local != "Record" || xmlAttr(start, "type") != appleHealthCaffeineType {
+			continue
+		}
+
+		record, err := appleHealthCaffeineRecord(start)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid Apple Health export: record %d: %v", len(records)+1, err)
+		}
+		if i.MaxRecords > 0 && len(records) == i.MaxRecords {
+			return nil, nil, fmt.Errorf("too many caffeine records (at most %d)", i.MaxRecords)
+		}
+		records = append(records, record)
+	}
+	if !sawHealthData {
+		return nil, nil, fmt.Errorf("not an Apple Health export: no HealthData element")
+	}
+
+	columns := map[string]bool{AppleHealthStartDate: true, AppleHealthCaffeineMg: true, AppleHealthSource: true}
+	return columns, records, nil
+}
+
+// appleHealthCaffeineRecord converts a caffeine Record element into a row
+func appleHealthCaffeineRecord(start xml.StartElement) (map[string]string, error) {
+	startDate, err := time.Parse(appleHealthDateLayout, xmlAttr(start, "startDate"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid startDate %q", xmlAttr(start, "startDate"))
+	}
+
+	value, err := strconv.ParseFloat(xmlAttr(start, "value"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q", xmlAttr(start, "value"))
+	}
+	switch unit := xmlAttr(start, "unit"); unit {
+	case "mg":
+	case "g":
+		value *= 1000
+	case "mcg":
+		value /= 1000
+	default:
+		return nil, fmt.Errorf("unsupported unit %q", unit)
+	}
+
+	return map[string]string{
+		AppleHealthStartDate:  startDate.Format(time.RFC3339),
+		AppleHealthCaffeineMg: strconv.Itoa(int(math.Round(value))),
+		AppleHealthSource:     xmlAttr(start, "sourceName"),
+	}, nil
+}
+
+// xmlAttr returns the value of the named attribute, or "" when it is missing
+func xmlAttr(start xml.StartElement, name string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,183 @@
+// file: internal/infrastructure/importers/icalendar.go
+package importers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/usecases"
+)
+
+// ICalendarImporter implements EntryImporter
+var _ usecases.EntryImporter = (*ICalendarImporter)(nil)
+
+// Columns of an iCalendar event row
+const (
+	ICalStart       = "DTSTART"     // RFC 3339, or without a zone for floating times
+	ICalSummary     = "SUMMARY"     // e.g. "Flat white"; map it to "type" when it holds coffee type names
+	ICalDescription = "DESCRIPTION" // unescaped, may span lines
+	ICalLocation    = "LOCATION"
+	ICalLatitude    = "latitude"  // from GEO
+	ICalLongitude   = "longitude" // from GEO
+)
+
+// ICalendarImporter reads the VEVENTs of an iCalendar (.ics) file, one entry per event,
+// e.g. from a calendar people used to log their coffees
+type ICalendarImporter struct {
+	MaxEvents int // stop with an error past this many events; 0 means no limit
+}
+
+func NewICalendarImporter(maxEvents int) *ICalendarImporter {
+	return &ICalendarImporter{MaxEvents: maxEvents}
+}
+
+func (i *ICalendarImporter) DefaultMapping() map[string]string {
+	return map[string]string{
+		entities.ImportFieldTimestamp: ICalStart,
+		entities.ImportFieldNotes:     ICalSummary,
+		entities.ImportFieldLatitude:  ICalLatitude,
+		entities.ImportFieldLongitude: ICalLongitude,
+	}
+}
+
+// Read returns no common columns: events only carry the properties they were given
+func (i *ICalendarImporter) Read(data io.Reader) (map[string]bool, []map[string]string, error) {
+	lines, err := unfoldICalLines(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid iCalendar file: %v", err)
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, nil, fmt.Errorf("not an iCalendar file: missing BEGIN:VCALENDAR")
+	}
+
+	var records []map[string]string
+	var event map[string]string
+	for n, line := range lines {
+		name, params, value, ok := parseICalLine(line)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid iCalendar file: malformed line %d", n+1)
+		}
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			event = make(map[string]string)
+		case name == "END" && strings.EqualFold(value, "VEVENT") && event != nil:
+			if i.MaxEvents > 0 && len(records) == i.MaxEvents {
+				return nil, nil, fmt.Errorf("too many events (at most %d)", i.MaxEvents)
+			}
+			records = append(records, event)
+			event = nil
+		case event == nil:
+			// Calendar properties and other components (VTIMEZONE, VALARM...) are not entries
+		case name == ICalStart:
+			start, err := parseICalTime(value, params)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid iCalendar file: event %d: %v", len(records)+1, err)
+			}
+			event[ICalStart] = start
+		case name == "GEO":
+			if lat, lon, found := strings.Cut(value, ";"); found {
+				event[ICalLatitude], event[ICalLongitude] = lat, lon
+			}
+		case name == ICalSummary || name == ICalDescription || name == ICalLocation:
+			event[name] = unescapeICalText(value)
+		}
+	}
+
+	return nil, records, nil
+}
+
+// unfoldICalLines joins continuation lines (starting with a space or tab) to the line before them
+func unfoldICalLines(data io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(data)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// parseICalLine splits "NAME;PARAM=x;PARAM=y:value"; the name is upper-cased
+func parseICalLine(line string) (string, map[string]string, string, bool) {
+	head, value, found := strings.Cut(line, ":")
+	if !found {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(head, ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, part := range parts[1:] {
+		key, val, _ := strings.Cut(part, "=")
+		params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, value, true
+}
+
+// parseICalTime converts a DATE-TIME or DATE value to RFC 3339. UTC ("Z") and TZID times keep their
+// zone; floating times and dates are returned without one, to be read in the import's timezone.
+func parseICalTime(value string, params map[string]string) (string, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.Parse("20060102", value)
+		if err != nil {
+			return "", fmt.Errorf("invalid date %q", value)
+		}
+		return t.Format("2006-01-02"), nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return "", fmt.Errorf("invalid time %q", value)
+		}
+		return t.Format(time.RFC3339), nil
+	}
+
+	if tzid := params["TZID"]; tzid != "" {
+		if loc, err := time.LoadLocation(tzid); err == nil {
+			t, err := time.ParseInLocation("20060102T150405", value, loc)
+			if err != nil {
+				return "", fmt.Errorf("invalid time %q", value)
+			}
+			return t.Format(time.RFC3339), nil
+		}
+		// Unknown (e.g. Windows) zone names fall back to a floating time
+	}
+
+	t, err := time.Parse("20060102T150405", value)
+	if err != nil {
+		return "", fmt.Errorf("invalid time %q", value)
+	}
+	return t.Format("2006-01-02T15:04:05"), nil
+}
+
+// unescapeICalText undoes the TEXT escaping of RFC 5545 (\n, \, \; \\)
+func unescapeICalText(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			i++
+			switch value[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(value[i])
+			}
+			continue
+		}
+		b.WriteByte(value[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,56 @@
+// file: internal/infrastructure/ratelimit/limiter.go
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests per Period as a token bucket: a full bucket holds Requests tokens and
+// refills evenly over Period, so bursts up to Requests are allowed. The zero Limit means no limit.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Enabled reports whether the limit restricts anything
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// ParseLimit reads a limit written as "<requests>/<period>", e.g. "5/1h" or "600/1m".
+// An empty string or "off" is the zero Limit. The period must leave at least 1ns per request.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "off" {
+		return Limit{}, nil
+	}
+
+	requests, period, found := strings.Cut(s, "/")
+	if !found {
+		return Limit{}, fmt.Errorf("expected <requests>/<period>, got %q", s)
+	}
+	n, err := strconv.Atoi(requests)
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid number of requests %q", requests)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid period %q", period)
+	}
+	if d < time.Duration(n) {
+		return Limit{}, fmt.Errorf("period %q is too short for %d requests", period, n)
+	}
+	return Limit{Requests: n, Period: d}, nil
+}
+
+// Store keeps the token buckets. MemoryStore serves a single instance; run several instances
+// behind a load balancer with a shared implementation (e.g. on Redis) so they count together.
+type Store interface {
+	// Take removes a token from the bucket of key. When the bucket is empty it returns false and
+	// how long until a token is available.
+	Take(ctx context.Context, key string, limit Limit) (allowed bool, retryAfter time.Duration, err error)
+}
@@ -0,0 +1,41 @@
+// file: internal/infrastructure/ratelimit/limiter_test.go
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Limit
+		wantErr bool
+	}{
+		{in: "", want: Limit{}},
+		{in: "off", want: Limit{}},
+		{in: " 5/1h ", want: Limit{Requests: 5, Period: time.Hour}},
+		{in: "600/1m", want: Limit{Requests: 600, Period: time.Minute}},
+		{in: "1000/1us", want: Limit{Requests: 1000, Period: time.Microsecond}},
+		{in: "1001/1us", wantErr: true}, // less than 1ns per request
+		{in: "5", wantErr: true},
+		{in: "0/1h", wantErr: true},
+		{in: "-1/1h", wantErr: true},
+		{in: "x/1h", wantErr: true},
+		{in: "5/0s", wantErr: true},
+		{in: "5/-1h", wantErr: true},
+		{in: "5/hour", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseLimit(%q) = %+v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseLimit(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// file: internal/infrastructure/ratelimit/memory_store_test.go
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock is a MemoryStore clock moved by hand
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestStore(clock *fakeClock) *MemoryStore {
+	store := NewMemoryStore()
+	store.now = clock.now
+	return store
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	limit := Limit{Requests: 3, Period: 3 * time.Minute} // a token per minute, bursts of 3
+
+	type step struct {
+		advance    time.Duration
+		key        string
+		allowed    bool
+		retryAfter time.Duration
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "burst up to the capacity, then wait for a token",
+			steps: []step{
+				{key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{key: "a", allowed: false, retryAfter: time.Minute},
+				{advance: 20 * time.Second, key: "a", allowed: false, retryAfter: 40 * time.Second},
+			},
+		},
+		{
+			name: "refills one token per period/requests",
+			steps: []step{
+				{key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{advance: time.Minute, key: "a", allowed: true},
+				{key: "a", allowed: false, retryAfter: time.Minute},
+			},
+		},
+		{
+			name: "refill stops at the capacity",
+			steps: []step{
+				{key: "a", allowed: true},
+				{advance: time.Hour, key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{key: "a", allowed: false, retryAfter: time.Minute},
+			},
+		},
+		{
+			name: "keys have their own bucket",
+			steps: []step{
+				{key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{key: "a", allowed: true},
+				{key: "a", allowed: false, retryAfter: time.Minute},
+				{key: "b", allowed: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+			store := newTestStore(clock)
+			for i, s := range tt.steps {
+				clock.advance(s.advance)
+				allowed, retryAfter, err := store.Take(context.Background(), s.key, limit)
+				if err != nil || allowed != s.allowed || retryAfter != s.retryAfter {
+					t.Fatalf("step %d: Take(%q) = %v, %v, %v; want %v, %v", i, s.key, allowed, retryAfter, err, s.allowed, s.retryAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreDisabledLimit(t *testing.T) {
+	store := NewMemoryStore()
+	for i := 0; i < 100; i++ {
+		if allowed, _, err := store.Take(context.Background(), "a", Limit{}); err != nil || !allowed {
+			t.Fatalf("Take with the zero Limit = %v, %v; want allowed", allowed, err)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"time"
 
+	"coffee-tracker-backend/internal/entities"
 	"coffee-tracker-backend/internal/infrastructure/utils"
 	"coffee-tracker-backend/internal/repositories"
 
@@ -56,6 +57,13 @@ func (r *AuthRepositoryImpl) InvalidateOTP(ctx context.Context, userID uuid.UUID
     return err
 }
 
+// InvalidateUserOTPs marks every unused OTP of the user as used
+func (r *AuthRepositoryImpl) InvalidateUserOTPs(ctx context.Context, userID uuid.UUID) error {
+	query := `UPDATE user_otps SET used = TRUE WHERE user_id = $1 AND used = FALSE`
+	_, err := r.db.ExecContext(ctx, query, userID)
+	return err
+}
+
 // SaveRefreshToken inserts or updates a refresh token for a device
 func (r *AuthRepositoryImpl) SaveRefreshToken(ctx context.Context, userID, deviceID uuid.UUID, token string, expiresAt time.Time) error {
 
@@ -129,3 +137,28 @@ func (r *AuthRepositoryImpl) InvalidateAllUserTokens(ctx context.Context, userID
 	_, err := r.db.ExecContext(ctx, query, userID)
 	return err
 }
+
+// ListDeviceSessions returns the user's devices with an unexpired refresh token
+func (r *AuthRepositoryImpl) ListDeviceSessions(ctx context.Context, userID uuid.UUID) ([]entities.DeviceSession, error) {
+	query := `
+		SELECT device_id, expires_at, updated_at
+		FROM user_refresh_tokens
+		WHERE user_id = $1 AND expires_at > $2
+		ORDER BY updated_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID, utils.NowUTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]entities.DeviceSession, 0)
+	for rows.Next() {
+		var session entities.DeviceSession
+		if err := rows.Scan(&session.DeviceID, &session.ExpiresAt, &session.UpdatedAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
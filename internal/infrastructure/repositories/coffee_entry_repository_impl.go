@@ -4,6 +4,10 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
 	"time"
 
 	"coffee-tracker-backend/internal/entities"
@@ -13,194 +17,699 @@ import (
 	"github.com/google/uuid"
 )
 
+// coffeeEntryColumns is the column list matching scanCoffeeEntry
+const coffeeEntryColumns = `id, user_id, notes, coffee_type_id, size_id, caffeine_mg, price, currency, rating, latitude, longitude, timestamp, created_at, updated_at, version, created_version, deleted_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanCoffeeEntry scans the coffeeEntryColumns, followed by any extra selected columns into extra
+func scanCoffeeEntry(row rowScanner, extra ...any) (*entities.CoffeeEntry, error) {
+	var entry entities.CoffeeEntry
+	dest := []any{
+		&entry.ID,
+		&entry.UserID,
+		&entry.Notes,
+		&entry.CoffeeTypeID,
+		&entry.SizeID,
+		&entry.Caffeine,
+		&entry.Price,
+		&entry.Currency,
+		&entry.Rating,
+		&entry.Latitude,
+		&entry.Longitude,
+		&entry.Timestamp,
+		&entry.CreatedAt,
+		&entry.UpdatedAt,
+		&entry.Version,
+		&entry.CreatedVersion,
+		&entry.DeletedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func scanCoffeeEntries(rows *sql.Rows) ([]*entities.CoffeeEntry, error) {
+	var entries []*entities.CoffeeEntry
+	for rows.Next() {
+		entry, err := scanCoffeeEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 type CoffeeEntryRepositoryImpl struct {
-	db *sql.DB
+	db   querier
+	conn *sql.DB // nil when the repository is bound to a transaction
 }
 
 func NewCoffeeEntryRepositoryImpl(db *sql.DB) repositories.CoffeeEntryRepository {
-	return &CoffeeEntryRepositoryImpl{db: db}
+	return &CoffeeEntryRepositoryImpl{db: db, conn: db}
+}
+
+func (r *CoffeeEntryRepositoryImpl) WithinTx(ctx context.Context, fn func(tx repositories.CoffeeEntryRepository) error) error {
+	if r.conn == nil {
+		// Already in a transaction, join it
+		return fn(r)
+	}
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(&CoffeeEntryRepositoryImpl{db: tx}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *CoffeeEntryRepositoryImpl) LockUser(ctx context.Context, userID uuid.UUID) error {
+	if r.conn != nil {
+		return errors.New("LockUser must be called within a transaction")
+	}
+	_, err := r.db.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('coffee_entries:' || $1::text))`, userID)
+	return err
+}
+
+// userLock is a CTE taking the LockUser lock of the user in the given parameter. Every statement
+// assigning versions joins it, so a user's versions commit in the order they are assigned and the
+// change feed never skips a version still in flight.
+func userLock(param string) string {
+	return `user_lock AS (SELECT pg_advisory_xact_lock(hashtext('coffee_entries:' || ` + param + `::text)))`
 }
 
 func (r *CoffeeEntryRepositoryImpl) Create(ctx context.Context, entry *entities.CoffeeEntry) error {
 	query := `
-    INSERT INTO coffee_entries (id, user_id, notes, coffee_type_id, size_id, latitude, longitude, timestamp)
-    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+    WITH ` + userLock("$2") + `,
+    v AS (SELECT nextval('coffee_entries_version_seq') AS version FROM user_lock)
+    INSERT INTO coffee_entries (id, user_id, notes, coffee_type_id, size_id, caffeine_mg, latitude, longitude, timestamp, price, currency, rating, created_at, updated_at, version, created_version)
+    SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, v.version, v.version FROM v
+    RETURNING version, created_version
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	err := r.db.QueryRowContext(ctx, query,
 		entry.ID,
 		entry.UserID,
 		utils.NullIfEmpty(entry.Notes),
 		entry.CoffeeTypeID,
 		entry.SizeID,
+		entry.Caffeine,
 		entry.Latitude,
 		entry.Longitude,
 		entry.Timestamp,
-	)
+		entry.Price,
+		entry.Currency,
+		entry.Rating,
+		entry.CreatedAt,
+		entry.UpdatedAt,
+	).Scan(&entry.Version, &entry.CreatedVersion)
 		
 	return err
 }
 
+// createManyBatch keeps a CreateMany statement under Postgres' 65535 bind parameters
+const createManyBatch = 1000
+
+// CreateMany inserts the entries with one statement per createManyBatch rows. The first row's
+// placeholders carry the column types, the VALUES list is not inserted directly.
+func (r *CoffeeEntryRepositoryImpl) CreateMany(ctx context.Context, userID uuid.UUID, entries []*entities.CoffeeEntry) error {
+	for start := 0; start < len(entries); start += createManyBatch {
+		batch := entries[start:min(start+createManyBatch, len(entries))]
+
+		args := []any{userID}
+		rows := make([]string, len(batch))
+		byID := make(map[uuid.UUID]*entities.CoffeeEntry, len(batch))
+		for i, entry := range batch {
+			n := len(args)
+			if i == 0 {
+				rows[i] = fmt.Sprintf("($%d::uuid, $%d::text, $%d::integer, $%d::integer, $%d::integer, $%d::double precision, $%d::double precision, $%d::timestamptz, $%d::numeric, $%d::text, $%d::integer, $%d::timestamptz, $%d::timestamptz)",
+					n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10, n+11, n+12, n+13)
+			} else {
+				rows[i] = fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+					n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10, n+11, n+12, n+13)
+			}
+			args = append(args,
+				entry.ID,
+				utils.NullIfEmpty(entry.Notes),
+				entry.CoffeeTypeID,
+				entry.SizeID,
+				entry.Caffeine,
+				entry.Latitude,
+				entry.Longitude,
+				entry.Timestamp,
+				entry.Price,
+				entry.Currency,
+				entry.Rating,
+				entry.CreatedAt,
+				entry.UpdatedAt,
+			)
+			byID[entry.ID] = entry
+		}
+
+		query := `
+    WITH ` + userLock("$1") + `,
+    input (id, notes, coffee_type_id, size_id, caffeine_mg, latitude, longitude, timestamp, price, currency, rating, created_at, updated_at) AS (
+        VALUES ` + strings.Join(rows, ", ") + `
+    ),
+    v AS (SELECT input.*, nextval('coffee_entries_version_seq') AS version FROM input, user_lock)
+    INSERT INTO coffee_entries (id, user_id, notes, coffee_type_id, size_id, caffeine_mg, latitude, longitude, timestamp, price, currency, rating, created_at, updated_at, version, created_version)
+    SELECT id, $1, notes, coffee_type_id, size_id, caffeine_mg, latitude, longitude, timestamp, price, currency, rating, created_at, updated_at, version, version FROM v
+    RETURNING id, version, created_version
+	`
+		result, err := r.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		for result.Next() {
+			var id uuid.UUID
+			var version, createdVersion int64
+			if err := result.Scan(&id, &version, &createdVersion); err != nil {
+				result.Close()
+				return err
+			}
+			if entry := byID[id]; entry != nil {
+				entry.Version, entry.CreatedVersion = version, createdVersion
+			}
+		}
+		if err := result.Err(); err != nil {
+			result.Close()
+			return err
+		}
+		result.Close()
+	}
+	return nil
+}
+
+// Update overwrites the entry's editable fields, scoped to its owner (entry.UserID).
+// Returns repositories.ErrNotFound when the user has no such entry.
 func (r *CoffeeEntryRepositoryImpl) Update(ctx context.Context, entry *entities.CoffeeEntry) error {
 	query := `
+		WITH ` + userLock("$2") + `
 		UPDATE coffee_entries 
-		SET notes = $2, timestamp = $3, coffee_type_id =$4, size_id=$5, updated_at = $6
-		WHERE id = $1
+		SET notes = $3, timestamp = $4, coffee_type_id =$5, size_id=$6, caffeine_mg = $7, price = $8, currency = $9, rating = $10, updated_at = $11,
+			version = nextval('coffee_entries_version_seq')
+		FROM user_lock
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		RETURNING version
 	`
 	
-	_, err := r.db.ExecContext(ctx, query,
+	err := r.db.QueryRowContext(ctx, query,
 		entry.ID,
+		entry.UserID,
 		utils.NullIfEmpty(entry.Notes),
 		entry.Timestamp,
 		entry.CoffeeTypeID,
 		entry.SizeID,
-		utils.NowUTC(),
-	)
+		entry.Caffeine,
+		entry.Price,
+		entry.Currency,
+		entry.Rating,
+		entry.UpdatedAt,
+	).Scan(&entry.Version)
+	if err == sql.ErrNoRows {
+		return repositories.ErrNotFound
+	}
 	
 	return err
 }
 
+// Replace is Update including the location, and reads the stored entry back into entry
+func (r *CoffeeEntryRepositoryImpl) Replace(ctx context.Context, entry *entities.CoffeeEntry) error {
+	query := `
+		WITH ` + userLock("$2") + `
+		UPDATE coffee_entries
+		SET notes = $3, timestamp = $4, coffee_type_id = $5, size_id = $6, caffeine_mg = $7, price = $8, currency = $9, rating = $10,
+			latitude = $11, longitude = $12, updated_at = $13, version = nextval('coffee_entries_version_seq')
+		FROM user_lock
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+		RETURNING ` + coffeeEntryColumns
+
+	stored, err := scanCoffeeEntry(r.db.QueryRowContext(ctx, query,
+		entry.ID,
+		entry.UserID,
+		utils.NullIfEmpty(entry.Notes),
+		entry.Timestamp,
+		entry.CoffeeTypeID,
+		entry.SizeID,
+		entry.Caffeine,
+		entry.Price,
+		entry.Currency,
+		entry.Rating,
+		entry.Latitude,
+		entry.Longitude,
+		entry.UpdatedAt,
+	))
+	if err == sql.ErrNoRows {
+		return repositories.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	*entry = *stored
+	return nil
+}
+
+// GetByID returns the entry with the given id, whoever owns it, or repositories.ErrNotFound
 func (r *CoffeeEntryRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entities.CoffeeEntry, error) {
 	query := `
-		SELECT id, user_id, notes, coffee_type_id, size_id, latitude, longitude, timestamp, created_at, updated_at
+		SELECT ` + coffeeEntryColumns + `
 		FROM coffee_entries
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 		LIMIT 1
 	`
 	
-	var entry entities.CoffeeEntry
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&entry.ID,
-		&entry.UserID,
-		&entry.Notes,
-		&entry.CoffeeTypeID,
-		&entry.SizeID,
-		&entry.Latitude,
-		&entry.Longitude,
-		&entry.Timestamp,
-		&entry.CreatedAt,
-		&entry.UpdatedAt,
-	)
-	
+	entry, err := scanCoffeeEntry(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repositories.ErrNotFound
+		}
 		return nil, err
 	}
 	
-	return &entry, nil
+	return entry, nil
 }
 
-func (r *CoffeeEntryRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.CoffeeEntry, error) {
+// GetOwner returns who the entry id belongs to, counting trashed and purged entries, or repositories.ErrNotFound
+func (r *CoffeeEntryRepositoryImpl) GetOwner(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	query := `
+		SELECT user_id FROM coffee_entries WHERE id = $1
+		UNION ALL
+		SELECT user_id FROM coffee_entry_tombstones WHERE entry_id = $1
+		LIMIT 1
+	`
+
+	var owner uuid.UUID
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&owner)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, repositories.ErrNotFound
+	}
+	return owner, err
+}
+
+// coffeeEntrySortClauses maps each sort option to its ORDER BY clause. Nullable columns sort
+// missing values last in both directions, and id keeps the order stable across pages.
+var coffeeEntrySortClauses = map[repositories.CoffeeEntrySort]string{
+	repositories.SortTimestampAsc:  "timestamp ASC, id ASC",
+	repositories.SortTimestampDesc: "timestamp DESC, id DESC",
+	repositories.SortCaffeineAsc:   "caffeine_mg ASC NULLS LAST, timestamp ASC, id ASC",
+	repositories.SortCaffeineDesc:  "caffeine_mg DESC NULLS LAST, timestamp DESC, id DESC",
+	repositories.SortRatingAsc:     "rating ASC NULLS LAST, timestamp ASC, id ASC",
+	repositories.SortRatingDesc:    "rating DESC NULLS LAST, timestamp DESC, id DESC",
+}
+
+// entryConditions accumulates WHERE conditions and their positional arguments
+type entryConditions struct {
+	conds []string
+	args  []any
+}
+
+// arg registers a query argument and returns its placeholder
+func (c *entryConditions) arg(value any) string {
+	c.args = append(c.args, value)
+	return fmt.Sprintf("$%d", len(c.args))
+}
+
+func (c *entryConditions) add(cond string) {
+	c.conds = append(c.conds, cond)
+}
+
+func (c *entryConditions) where() string {
+	return strings.Join(c.conds, " AND ")
+}
+
+func (r *CoffeeEntryRepositoryImpl) List(ctx context.Context, filter repositories.CoffeeEntryFilter) ([]*entities.CoffeeEntry, error) {
+	var c entryConditions
+	c.add("user_id = " + c.arg(filter.UserID))
+	c.add("deleted_at IS NULL")
+
+	if filter.From != nil {
+		c.add("timestamp >= " + c.arg(*filter.From))
+	}
+	if filter.To != nil {
+		c.add("timestamp < " + c.arg(*filter.To))
+	}
+	if filter.CoffeeTypeID != nil {
+		c.add("coffee_type_id = " + c.arg(*filter.CoffeeTypeID))
+	}
+	if filter.SizeID != nil {
+		c.add("size_id = " + c.arg(*filter.SizeID))
+	}
+	if filter.HasNotes != nil {
+		if *filter.HasNotes {
+			c.add("notes IS NOT NULL AND notes <> ''")
+		} else {
+			c.add("(notes IS NULL OR notes = '')")
+		}
+	}
+	if filter.HasLocation != nil {
+		if *filter.HasLocation {
+			c.add("latitude IS NOT NULL AND longitude IS NOT NULL")
+		} else {
+			c.add("(latitude IS NULL OR longitude IS NULL)")
+		}
+	}
+
+	if filter.After != nil {
+		// Row comparison matches the (timestamp, id) order, so no entry is skipped or repeated
+		// when entries are inserted between pages
+		op := ">"
+		if filter.Sort == repositories.SortTimestampDesc {
+			op = "<"
+		}
+		c.add("(timestamp, id) " + op + " (" + c.arg(filter.After.Timestamp) + ", " + c.arg(filter.After.ID) + ")")
+	}
+
+	orderBy, ok := coffeeEntrySortClauses[filter.Sort]
+	if !ok {
+		orderBy = coffeeEntrySortClauses[repositories.SortTimestampAsc]
+	}
+
 	query := `
-		SELECT id, user_id, notes, coffee_type_id, size_id, latitude, longitude, timestamp, created_at, updated_at
+		SELECT ` + coffeeEntryColumns + `
 		FROM coffee_entries
-		WHERE user_id = $1
-		ORDER BY timestamp ASC
-		LIMIT $2 OFFSET $3
+		WHERE ` + c.where() + `
+		ORDER BY ` + orderBy + `
+		LIMIT ` + c.arg(filter.Limit) + ` OFFSET ` + c.arg(filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, c.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCoffeeEntries(rows)
+}
+
+// Search matches are marked with private-use characters, which are removed from the notes first,
+// so the notes can be HTML-escaped before the markers become <mark> tags
+const (
+	snippetStartSel = "\ue000"
+	snippetStopSel  = "\ue001"
+)
+
+// highlightSnippet escapes a ts_headline snippet for HTML and wraps its matches in <mark></mark>
+func highlightSnippet(snippet string) string {
+	return strings.NewReplacer(snippetStartSel, "<mark>", snippetStopSel, "</mark>").Replace(html.EscapeString(snippet))
+}
+
+func (r *CoffeeEntryRepositoryImpl) SearchNotes(ctx context.Context, userID uuid.UUID, search string, limit int) ([]entities.EntrySearchResult, error) {
+	query := `
+		SELECT ` + coffeeEntryColumns + `,
+			ts_rank(notes_tsv, q) AS rank,
+			ts_headline('simple', translate(notes, $4, ''), q, $5)
+		FROM coffee_entries, websearch_to_tsquery('simple', $2) AS q
+		WHERE user_id = $1 AND deleted_at IS NULL AND notes_tsv @@ q
+		ORDER BY rank DESC, timestamp DESC, id DESC
+		LIMIT $3
 	`
-	
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+
+	headlineOptions := fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=20, MinWords=5, MaxFragments=2", snippetStartSel, snippetStopSel)
+	rows, err := r.db.QueryContext(ctx, query, userID, search, limit, snippetStartSel+snippetStopSel, headlineOptions)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	
-	var entries []*entities.CoffeeEntry
+
+	results := make([]entities.EntrySearchResult, 0)
 	for rows.Next() {
-		var entry entities.CoffeeEntry
-		err := rows.Scan(
-			&entry.ID,
-			&entry.UserID,
-			&entry.Notes,
-			&entry.CoffeeTypeID,
-			&entry.SizeID,
-			&entry.Latitude,
-			&entry.Longitude,
-			&entry.Timestamp,
-			&entry.CreatedAt,
-			&entry.UpdatedAt,
-		)
+		var result entities.EntrySearchResult
+		entry, err := scanCoffeeEntry(rows, &result.Rank, &result.Snippet)
 		if err != nil {
 			return nil, err
 		}
-		entries = append(entries, &entry)
+		result.Entry = entry
+		result.Snippet = highlightSnippet(result.Snippet)
+		results = append(results, result)
 	}
-	
-	return entries, rows.Err()
+
+	return results, rows.Err()
 }
 
-func (r *CoffeeEntryRepositoryImpl) GetByUserIDAndDateRange(ctx context.Context, userID uuid.UUID, limit int, offset int, startDate, endDate time.Time) ([]*entities.CoffeeEntry, error) {
-	
+func (r *CoffeeEntryRepositoryImpl) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	return r.DeleteAt(ctx, id, userID, utils.NowUTC())
+}
+
+// DeleteAt moves the entry to the trash. The new version makes the delete show up in the change feed.
+// deleted_at is always the server time, so the trash retention can't be shortened by a client clock;
+// the caller's time of the delete goes to updated_at, which sync compares against.
+// Returns repositories.ErrNotFound when the user has no such (non-deleted) entry.
+func (r *CoffeeEntryRepositoryImpl) DeleteAt(ctx context.Context, id uuid.UUID, userID uuid.UUID, deletedAt time.Time) error {
 	query := `
-		SELECT id, user_id, notes, coffee_type_id, size_id, latitude, longitude, timestamp, created_at, updated_at
+		WITH ` + userLock("$2") + `
+		UPDATE coffee_entries
+		SET deleted_at = $4, updated_at = $3, version = nextval('coffee_entries_version_seq')
+		FROM user_lock
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+	`
+	res, err := r.db.ExecContext(ctx, query, id, userID, deletedAt, utils.NowUTC())
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+func (r *CoffeeEntryRepositoryImpl) DeleteAll(ctx context.Context, userID uuid.UUID, from, to *time.Time) (int64, error) {
+	var c entryConditions
+	deletedAt := c.arg(utils.NowUTC())
+	lockUser := c.arg(userID)
+	addRangeConditions(&c, userID, from, to)
+
+	query := `
+		WITH ` + userLock(lockUser) + `
+		UPDATE coffee_entries
+		SET deleted_at = ` + deletedAt + `, updated_at = ` + deletedAt + `, version = nextval('coffee_entries_version_seq')
+		FROM user_lock
+		WHERE ` + c.where()
+
+	result, err := r.db.ExecContext(ctx, query, c.args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// addRangeConditions restricts a query to the user's live entries with a timestamp in [from, to)
+func addRangeConditions(c *entryConditions, userID uuid.UUID, from, to *time.Time) {
+	c.add("user_id = " + c.arg(userID))
+	c.add("deleted_at IS NULL")
+	if from != nil {
+		c.add("timestamp >= " + c.arg(*from))
+	}
+	if to != nil {
+		c.add("timestamp < " + c.arg(*to))
+	}
+}
+
+func (r *CoffeeEntryRepositoryImpl) StreamEntries(ctx context.Context, userID uuid.UUID, from, to *time.Time, fn func(*entities.CoffeeEntry) error) error {
+	var c entryConditions
+	addRangeConditions(&c, userID, from, to)
+
+	query := `
+		SELECT ` + coffeeEntryColumns + `
 		FROM coffee_entries
-		WHERE user_id = $1 
-		AND timestamp >= $4 AND timestamp < $5
-		ORDER BY timestamp ASC
+		WHERE ` + c.where() + `
+		ORDER BY timestamp ASC, id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, c.args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanCoffeeEntry(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *CoffeeEntryRepositoryImpl) ListDeleted(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.CoffeeEntry, error) {
+	query := `
+		SELECT ` + coffeeEntryColumns + `
+		FROM coffee_entries
+		WHERE user_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
 		LIMIT $2 OFFSET $3
 	`
-	
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset, startDate, endDate)
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	
-	var entries []*entities.CoffeeEntry
-	for rows.Next() {
-		var entry entities.CoffeeEntry
-		err := rows.Scan(
-			&entry.ID,
-			&entry.UserID,
-			&entry.Notes,
-			&entry.CoffeeTypeID,
-			&entry.SizeID,
-			&entry.Latitude,
-			&entry.Longitude,
-			&entry.Timestamp,
-			&entry.CreatedAt,
-			&entry.UpdatedAt,
+
+	return scanCoffeeEntries(rows)
+}
+
+// Restore takes the entry out of the trash, or returns repositories.ErrNotFound when it is not there
+func (r *CoffeeEntryRepositoryImpl) Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*entities.CoffeeEntry, error) {
+	query := `
+		WITH ` + userLock("$2") + `
+		UPDATE coffee_entries
+		SET deleted_at = NULL, updated_at = $3, version = nextval('coffee_entries_version_seq')
+		FROM user_lock
+		WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+		RETURNING ` + coffeeEntryColumns
+
+	entry, err := scanCoffeeEntry(r.db.QueryRowContext(ctx, query, id, userID, utils.NowUTC()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+// PurgeDeleted permanently removes the entries of all users deleted before the given time. Each one
+// leaves a tombstone with the time and version of its delete, so devices that already synced it see no change.
+func (r *CoffeeEntryRepositoryImpl) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		WITH purged AS (
+			DELETE FROM coffee_entries
+			WHERE deleted_at IS NOT NULL AND deleted_at < $1
+			RETURNING id, user_id, updated_at, version
 		)
-		if err != nil {
+		INSERT INTO coffee_entry_tombstones (entry_id, user_id, deleted_at, version)
+		SELECT id, user_id, updated_at, version FROM purged
+		ON CONFLICT (entry_id) DO UPDATE
+		SET deleted_at = EXCLUDED.deleted_at, version = EXCLUDED.version
+	`
+	res, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *CoffeeEntryRepositoryImpl) GetChangedSince(ctx context.Context, userID uuid.UUID, version int64, limit int) ([]*entities.CoffeeEntry, error) {
+	query := `
+		SELECT ` + coffeeEntryColumns + `
+		FROM coffee_entries
+		WHERE user_id = $1 AND deleted_at IS NULL AND version > $2
+		ORDER BY version ASC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, version, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCoffeeEntries(rows)
+}
+
+// Deletes are the entries in the trash plus the tombstones of purged entries. The time of a delete
+// is the updated_at of the trashed entry (see DeleteAt), which its tombstone keeps.
+const deletedEntriesQuery = `
+	SELECT id AS entry_id, user_id, updated_at AS deleted_at, version
+	FROM coffee_entries
+	WHERE deleted_at IS NOT NULL
+	UNION ALL
+	SELECT entry_id, user_id, deleted_at, version
+	FROM coffee_entry_tombstones`
+
+func (r *CoffeeEntryRepositoryImpl) GetTombstonesSince(ctx context.Context, userID uuid.UUID, version int64, limit int) ([]entities.EntryTombstone, error) {
+	query := `
+		SELECT entry_id, deleted_at, version
+		FROM (` + deletedEntriesQuery + `) AS deleted
+		WHERE user_id = $1 AND version > $2
+		ORDER BY version ASC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, version, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tombstones []entities.EntryTombstone
+	for rows.Next() {
+		var t entities.EntryTombstone
+		if err := rows.Scan(&t.ID, &t.DeletedAt, &t.Version); err != nil {
 			return nil, err
 		}
-		entries = append(entries, &entry)
+		tombstones = append(tombstones, t)
 	}
-	
-	return entries, rows.Err()
+
+	return tombstones, rows.Err()
 }
 
-func (r *CoffeeEntryRepositoryImpl) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
-	query := `DELETE FROM coffee_entries WHERE id = $1 AND user_id = $2`
-	_, err := r.db.ExecContext(ctx, query, id, userID)
-	return err
+// GetTombstone returns the user's delete of an entry id (trashed or purged), or repositories.ErrNotFound
+func (r *CoffeeEntryRepositoryImpl) GetTombstone(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*entities.EntryTombstone, error) {
+	query := `
+		SELECT entry_id, deleted_at, version
+		FROM (` + deletedEntriesQuery + `) AS deleted
+		WHERE entry_id = $1 AND user_id = $2
+		LIMIT 1
+	`
+
+	var t entities.EntryTombstone
+	err := r.db.QueryRowContext(ctx, query, id, userID).Scan(&t.ID, &t.DeletedAt, &t.Version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
 }
 
-func (r *CoffeeEntryRepositoryImpl) DeleteAll(ctx context.Context, userID uuid.UUID) error {
-	query := `DELETE FROM coffee_entries WHERE user_id = $1`
-	_, err := r.db.ExecContext(ctx, query, userID)
+func (r *CoffeeEntryRepositoryImpl) DeleteTombstone(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	query := `DELETE FROM coffee_entry_tombstones WHERE entry_id = $1 AND user_id = $2`
+	_, err := r.db.ExecContext(ctx, query, id, userID)
 	return err
 }
 
-
-func (r *CoffeeEntryRepositoryImpl) GetStats(ctx context.Context, userID uuid.UUID) (*entities.CoffeeStats, error) {
+func (r *CoffeeEntryRepositoryImpl) GetStats(ctx context.Context, userID uuid.UUID, languageCode string) (*entities.CoffeeStats, error) {
 
 	query := `
 		SELECT 
 			COUNT(*) as total_entries,
-			--COALESCE(SUM(caffeine_mg), 0) as total_caffeine,
-			--COALESCE(AVG(rating), 0) as average_rating,
-			--COALESCE(SUM(price), 0) as total_spent,
-			(SELECT COUNT(*) FROM coffee_entries WHERE user_id = $1 AND timestamp >= $2 - INTERVAL '7 days') as entries_this_week,
-			(SELECT COUNT(*) FROM coffee_entries WHERE user_id = $1 AND timestamp >= $2 - INTERVAL '30 days') as entries_this_month
+			COALESCE(SUM(caffeine_mg), 0) as total_caffeine,
+			ROUND(AVG(rating), 2)::float8 as average_rating,
+			(SELECT COUNT(*) FROM coffee_entries WHERE user_id = $1 AND deleted_at IS NULL AND timestamp >= $2 - INTERVAL '7 days') as entries_this_week,
+			(SELECT COUNT(*) FROM coffee_entries WHERE user_id = $1 AND deleted_at IS NULL AND timestamp >= $2 - INTERVAL '30 days') as entries_this_month
 		FROM coffee_entries 
-		WHERE user_id = $1
+		WHERE user_id = $1 AND deleted_at IS NULL
 	`
 	
 	var stats entities.CoffeeStats
 	err := r.db.QueryRowContext(ctx, query, userID, utils.NowUTC()).Scan(
 		&stats.TotalEntries,
-		// &stats.TotalCaffeine,
-		// &stats.AverageRating,
-		// &stats.TotalSpent,
+		&stats.TotalCaffeine,
+		&stats.AverageRating,
 		&stats.EntriesThisWeek,
 		&stats.EntriesThisMonth,
 	)
@@ -208,32 +717,183 @@ func (r *CoffeeEntryRepositoryImpl) GetStats(ctx context.Context, userID uuid.UU
 	if err != nil {
 		return nil, err
 	}
-/*
-	// Get favorite coffee type
-	favoriteQuery := `
-		SELECT coffee_type
-		FROM coffee_entries 
-		WHERE user_id = $1
-		GROUP BY coffee_type
-		ORDER BY COUNT(*) DESC
-		LIMIT 1
-	`
-	
-	var favoriteCoffee sql.NullString
-	err = r.db.QueryRowContext(ctx, favoriteQuery, userID).Scan(&favoriteCoffee)
-	if err != nil && err != sql.ErrNoRows {
+	// Breakdown per coffee type and size, most consumed first
+	stats.TypeBreakdown, err = r.getBreakdown(ctx, typeBreakdownQuery, userID, languageCode)
+	if err != nil {
 		return nil, err
 	}
-	
-	if favoriteCoffee.Valid {
-		stats.FavoriteCoffee = favoriteCoffee.String
+	stats.SizeBreakdown, err = r.getBreakdown(ctx, sizeBreakdownQuery, userID, languageCode)
+	if err != nil {
+		return nil, err
 	}
-*/	
+
+	stats.TotalSpent, err = r.getSpendingTotals(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.RatingByType, err = r.getRatingByType(ctx, userID, languageCode)
+	if err != nil {
+		return nil, err
+	}
+	stats.RatingByLocation, err = r.getRatingByLocation(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.FavoriteCoffee = favoriteOf(stats.TypeBreakdown)
+	stats.FavoriteSize = favoriteOf(stats.SizeBreakdown)
+
 	return &stats, nil
 }
 
+// Breakdown queries return (id, localized name, count, share of all entries).
+// Names come from the same translation tables as the KV lookups; entries without a type/size are grouped under a NULL id.
+const (
+	typeBreakdownQuery = `
+		SELECT e.coffee_type_id, COALESCE(ctt.name, ''), COUNT(*) AS cnt,
+			COUNT(*)::float8 / SUM(COUNT(*)) OVER () AS share
+		FROM coffee_entries e
+		LEFT JOIN coffee_type_translations ctt ON ctt.coffee_type_id = e.coffee_type_id
+			AND ctt.language_id = (SELECT id FROM languages WHERE code = $2)
+		WHERE e.user_id = $1 AND e.deleted_at IS NULL
+		GROUP BY e.coffee_type_id, ctt.name
+		ORDER BY cnt DESC, e.coffee_type_id ASC`
+
+	sizeBreakdownQuery = `
+		SELECT e.size_id, COALESCE(st.name, ''), COUNT(*) AS cnt,
+			COUNT(*)::float8 / SUM(COUNT(*)) OVER () AS share
+		FROM coffee_entries e
+		LEFT JOIN coffee_size_translations st ON st.coffee_size_id = e.size_id
+			AND st.language_id = (SELECT id FROM languages WHERE code = $2)
+		WHERE e.user_id = $1 AND e.deleted_at IS NULL
+		GROUP BY e.size_id, st.name
+		ORDER BY cnt DESC, e.size_id ASC`
+)
+
+func (r *CoffeeEntryRepositoryImpl) getBreakdown(ctx context.Context, query string, userID uuid.UUID, languageCode string) ([]entities.BreakdownItem, error) {
+	rows, err := r.db.QueryContext(ctx, query, userID, languageCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]entities.BreakdownItem, 0)
+	for rows.Next() {
+		var item entities.BreakdownItem
+		if err := rows.Scan(&item.ID, &item.Name, &item.Count, &item.Share); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// favoriteOf returns the most consumed item with a known id (items are sorted by count)
+func favoriteOf(items []entities.BreakdownItem) *entities.BreakdownItem {
+	for i := range items {
+		if items[i].ID != nil {
+			return &items[i]
+		}
+	}
+	return nil
+}
+
+// getRatingByType returns the average rating per coffee type, best rated first
+func (r *CoffeeEntryRepositoryImpl) getRatingByType(ctx context.Context, userID uuid.UUID, languageCode string) ([]entities.RatingItem, error) {
+	query := `
+		SELECT e.coffee_type_id, COALESCE(ctt.name, ''), ROUND(AVG(e.rating), 2)::float8 AS avg_rating, COUNT(*)
+		FROM coffee_entries e
+		LEFT JOIN coffee_type_translations ctt ON ctt.coffee_type_id = e.coffee_type_id
+			AND ctt.language_id = (SELECT id FROM languages WHERE code = $2)
+		WHERE e.user_id = $1 AND e.deleted_at IS NULL AND e.rating IS NOT NULL
+		GROUP BY e.coffee_type_id, ctt.name
+		ORDER BY avg_rating DESC, COUNT(*) DESC, e.coffee_type_id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, languageCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]entities.RatingItem, 0)
+	for rows.Next() {
+		var item entities.RatingItem
+		if err := rows.Scan(&item.ID, &item.Name, &item.Average, &item.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// getRatingByLocation returns the average rating per place, with coordinates rounded
+// to entities.LocationPrecision decimals. Most rated places first.
+func (r *CoffeeEntryRepositoryImpl) getRatingByLocation(ctx context.Context, userID uuid.UUID) ([]entities.LocationRating, error) {
+	query := `
+		SELECT ROUND(latitude::numeric, $2)::float8 AS lat,
+			ROUND(longitude::numeric, $2)::float8 AS lon,
+			ROUND(AVG(rating), 2)::float8,
+			COUNT(*)
+		FROM coffee_entries
+		WHERE user_id = $1 AND deleted_at IS NULL AND rating IS NOT NULL
+		AND latitude IS NOT NULL AND longitude IS NOT NULL
+		GROUP BY lat, lon
+		ORDER BY COUNT(*) DESC, lat ASC, lon ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, entities.LocationPrecision)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]entities.LocationRating, 0)
+	for rows.Next() {
+		var item entities.LocationRating
+		if err := rows.Scan(&item.Latitude, &item.Longitude, &item.Average, &item.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// getSpendingTotals returns the all-time spending per currency, largest number of priced entries first
+func (r *CoffeeEntryRepositoryImpl) getSpendingTotals(ctx context.Context, userID uuid.UUID) ([]entities.CurrencySpending, error) {
+	query := `
+		SELECT currency, SUM(price), COUNT(*)
+		FROM coffee_entries
+		WHERE user_id = $1 AND deleted_at IS NULL AND price IS NOT NULL
+		GROUP BY currency
+		ORDER BY COUNT(*) DESC, currency ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := make([]entities.CurrencySpending, 0)
+	for rows.Next() {
+		var total entities.CurrencySpending
+		if err := rows.Scan(&total.Currency, &total.Total, &total.Entries); err != nil {
+			return nil, err
+		}
+		total.AveragePerCup = entities.RoundMoney(total.Total / float64(total.Entries))
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
+}
+
 func (r *CoffeeEntryRepositoryImpl) GetCount(ctx context.Context, userID uuid.UUID) (int, error) {
-	query := `SELECT COUNT(*) FROM coffee_entries WHERE user_id = $1`
+	query := `SELECT COUNT(*) FROM coffee_entries WHERE user_id = $1 AND deleted_at IS NULL`
 	
 	var count int
 	err := r.db.QueryRowContext(ctx, query, userID).Scan(&count)
@@ -243,3 +903,177 @@ func (r *CoffeeEntryRepositoryImpl) GetCount(ctx context.Context, userID uuid.UU
 	
 	return count, nil
 }
+
+func (r *CoffeeEntryRepositoryImpl) GetCountInRange(ctx context.Context, userID uuid.UUID, from, to *time.Time) (int, error) {
+	var c entryConditions
+	addRangeConditions(&c, userID, from, to)
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM coffee_entries WHERE `+c.where(), c.args...).Scan(&count)
+	return count, err
+}
+
+func (r *CoffeeEntryRepositoryImpl) GetCaffeineTotal(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (int, int, error) {
+	query := `
+		SELECT COALESCE(SUM(caffeine_mg), 0), COUNT(*)
+		FROM coffee_entries
+		WHERE user_id = $1 AND deleted_at IS NULL AND timestamp >= $2 AND timestamp < $3
+	`
+
+	var totalCaffeine, entries int
+	err := r.db.QueryRowContext(ctx, query, userID, startDate, endDate).Scan(&totalCaffeine, &entries)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return totalCaffeine, entries, nil
+}
+
+func (r *CoffeeEntryRepositoryImpl) GetCaffeineIntakes(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]entities.CaffeineIntake, error) {
+	query := `
+		SELECT timestamp, caffeine_mg
+		FROM coffee_entries
+		WHERE user_id = $1 AND deleted_at IS NULL AND timestamp >= $2 AND timestamp < $3
+		AND caffeine_mg > 0
+		ORDER BY timestamp ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var intakes []entities.CaffeineIntake
+	for rows.Next() {
+		var intake entities.CaffeineIntake
+		if err := rows.Scan(&intake.Timestamp, &intake.CaffeineMg); err != nil {
+			return nil, err
+		}
+		intakes = append(intakes, intake)
+	}
+
+	return intakes, rows.Err()
+}
+
+func (r *CoffeeEntryRepositoryImpl) GetSeries(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, bucket entities.StatsBucket, timezone string) ([]entities.SeriesPoint, error) {
+	query := `
+		SELECT to_char(date_trunc($4, timestamp AT TIME ZONE $5), 'YYYY-MM-DD') AS bucket,
+			COUNT(*),
+			COALESCE(SUM(caffeine_mg), 0)
+		FROM coffee_entries
+		WHERE user_id = $1 AND deleted_at IS NULL AND timestamp >= $2 AND timestamp < $3
+		GROUP BY bucket
+		ORDER BY bucket ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, startDate, endDate, string(bucket), timezone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []entities.SeriesPoint
+	for rows.Next() {
+		var point entities.SeriesPoint
+		if err := rows.Scan(&point.Date, &point.Entries, &point.TotalCaffeine); err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+
+	return points, rows.Err()
+}
+
+func (r *CoffeeEntryRepositoryImpl) GetHeatmap(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, timezone string) ([]entities.HeatmapCell, error) {
+	query := `
+		SELECT EXTRACT(ISODOW FROM timestamp AT TIME ZONE $4)::int AS weekday,
+			EXTRACT(HOUR FROM timestamp AT TIME ZONE $4)::int AS hour,
+			COUNT(*)
+		FROM coffee_entries
+		WHERE user_id = $1 AND deleted_at IS NULL AND timestamp >= $2 AND timestamp < $3
+		GROUP BY weekday, hour
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, startDate, endDate, timezone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cells []entities.HeatmapCell
+	for rows.Next() {
+		var cell entities.HeatmapCell
+		if err := rows.Scan(&cell.ISOWeekday, &cell.Hour, &cell.Entries); err != nil {
+			return nil, err
+		}
+		cells = append(cells, cell)
+	}
+
+	return cells, rows.Err()
+}
+
+func (r *CoffeeEntryRepositoryImpl) GetDailyCounts(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, timezone string) ([]entities.DailyCount, error) {
+	query := `
+		SELECT to_char(timestamp AT TIME ZONE $4, 'YYYY-MM-DD') AS day,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE caffeine_mg IS NULL OR caffeine_mg > 0)
+		FROM coffee_entries
+		WHERE user_id = $1 AND deleted_at IS NULL AND timestamp >= $2 AND timestamp < $3
+		GROUP BY day
+		ORDER BY day ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, startDate, endDate, timezone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []entities.DailyCount
+	for rows.Next() {
+		var count entities.DailyCount
+		if err := rows.Scan(&count.Date, &count.Entries, &count.CaffeinatedEntries); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
+
+func (r *CoffeeEntryRepositoryImpl) GetSpending(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, bucket entities.StatsBucket, timezone string) ([]entities.SpendingPeriod, error) {
+	query := `
+		SELECT to_char(date_trunc($4, timestamp AT TIME ZONE $5), 'YYYY-MM-DD') AS bucket,
+			currency,
+			SUM(price),
+			COUNT(*)
+		FROM coffee_entries
+		WHERE user_id = $1 AND deleted_at IS NULL AND timestamp >= $2 AND timestamp < $3
+		AND price IS NOT NULL
+		GROUP BY bucket, currency
+		ORDER BY bucket ASC, currency ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, startDate, endDate, string(bucket), timezone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var periods []entities.SpendingPeriod
+	for rows.Next() {
+		var date string
+		var spending entities.CurrencySpending
+		if err := rows.Scan(&date, &spending.Currency, &spending.Total, &spending.Entries); err != nil {
+			return nil, err
+		}
+		if len(periods) == 0 || periods[len(periods)-1].Date != date {
+			periods = append(periods, entities.SpendingPeriod{Date: date})
+		}
+		last := &periods[len(periods)-1]
+		last.Currencies = append(last.Currencies, spending)
+	}
+
+	return periods, rows.Err()
+}
@@ -0,0 +1,42 @@
+// file: internal/infrastructure/repositories/consumed_token_repository_impl.go
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"coffee-tracker-backend/internal/repositories"
+)
+
+type ConsumedTokenRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewConsumedTokenRepositoryImpl(db *sql.DB) repositories.ConsumedTokenRepository {
+	return &ConsumedTokenRepositoryImpl{db: db}
+}
+
+// Consume relies on the primary key, so of two concurrent redemptions only one succeeds
+func (r *ConsumedTokenRepositoryImpl) Consume(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	query := `
+		INSERT INTO consumed_tokens (jti, expires_at)
+		VALUES ($1, $2)
+		ON CONFLICT (jti) DO NOTHING
+	`
+	res, err := r.db.ExecContext(ctx, query, jti, expiresAt)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := res.RowsAffected()
+	return inserted == 1, err
+}
+
+func (r *ConsumedTokenRepositoryImpl) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM consumed_tokens WHERE expires_at < $1`
+	res, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
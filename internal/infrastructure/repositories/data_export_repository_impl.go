@@ -0,0 +1,97 @@
+// file: internal/infrastructure/repositories/data_export_repository_impl.go
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/infrastructure/utils"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type DataExportRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewDataExportRepositoryImpl(db *sql.DB) repositories.DataExportRepository {
+	return &DataExportRepositoryImpl{db: db}
+}
+
+// CreatePending relies on the unique index on the users' pending exports, so of two concurrent
+// requests only one creates an export
+func (r *DataExportRepositoryImpl) CreatePending(ctx context.Context, export *entities.DataExport, staleBefore time.Time) (bool, error) {
+	expire := `
+		UPDATE data_exports SET status = $3, error = 'export timed out', completed_at = $4
+		WHERE user_id = $1 AND status = $2 AND created_at <= $5
+	`
+	_, err := r.db.ExecContext(ctx, expire, export.UserID, entities.DataExportPending, entities.DataExportFailed, utils.NowUTC(), staleBefore)
+	if err != nil {
+		return false, err
+	}
+
+	query := `
+		INSERT INTO data_exports (id, user_id, status, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) WHERE status = 'pending' DO NOTHING
+	`
+	res, err := r.db.ExecContext(ctx, query, export.ID, export.UserID, entities.DataExportPending, export.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	created, err := res.RowsAffected()
+	return created == 1, err
+}
+
+func (r *DataExportRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*entities.DataExport, error) {
+	query := `
+		SELECT id, user_id, status, COALESCE(object_path, ''), COALESCE(error, ''), created_at, completed_at
+		FROM data_exports
+		WHERE id = $1 AND user_id = $2
+	`
+	var export entities.DataExport
+	err := r.db.QueryRowContext(ctx, query, id, userID).Scan(
+		&export.ID, &export.UserID, &export.Status, &export.ObjectPath, &export.Error, &export.CreatedAt, &export.CompletedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *DataExportRepositoryImpl) MarkReady(ctx context.Context, id uuid.UUID, objectPath string) error {
+	query := `UPDATE data_exports SET status = $2, object_path = $3, completed_at = $4 WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, entities.DataExportReady, objectPath, utils.NowUTC())
+	return err
+}
+
+func (r *DataExportRepositoryImpl) MarkFailed(ctx context.Context, id uuid.UUID, message string) error {
+	query := `UPDATE data_exports SET status = $2, error = $3, completed_at = $4 WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, entities.DataExportFailed, message, utils.NowUTC())
+	return err
+}
+
+func (r *DataExportRepositoryImpl) ListObjectPaths(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	query := `SELECT object_path FROM data_exports WHERE user_id = $1 AND object_path IS NOT NULL`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, rows.Err()
+}
@@ -19,14 +19,14 @@ var (
 
 	kvQueries = map[int]string{
             // coffee types
-		1: `SELECT ct.id, ctt.name
+		repositories.KVCoffeeTypes: `SELECT ct.id, ctt.name
 			FROM coffee_types ct
 			JOIN coffee_type_translations ctt ON ct.id = ctt.coffee_type_id
 			JOIN languages l ON ctt.language_id = l.id
 			WHERE l.code = $1
 			ORDER BY ct.order_by ASC`,
             // coffee sizes
-		2: `SELECT s.id, st.name
+		repositories.KVCoffeeSizes: `SELECT s.id, st.name
 			FROM coffee_sizes s
 			JOIN coffee_size_translations st ON s.id = st.coffee_size_id
 			JOIN languages l ON st.language_id = l.id
@@ -86,3 +86,59 @@ func (r *GenericKVRepositoryImpl) GetKV(ctx context.Context, typeID int, languag
 
 	return items, nil
 }
+
+const caffeineModelCacheKey = "caffeine:model"
+
+func (r *GenericKVRepositoryImpl) GetCaffeineModel(ctx context.Context) (*entities.CaffeineModel, error) {
+	if cached, found := r.cache.Get(caffeineModelCacheKey); found {
+		if model, ok := cached.(*entities.CaffeineModel); ok {
+			return model, nil
+		}
+	}
+
+	model := &entities.CaffeineModel{
+		TypeMg:      make(map[int]int),
+		SizeFactors: make(map[int]float64),
+	}
+
+	typeRows, err := r.db.QueryContext(ctx, `SELECT id, caffeine_mg FROM coffee_types`)
+	if err != nil {
+		return nil, err
+	}
+	defer typeRows.Close()
+	for typeRows.Next() {
+		var id int
+		var mg sql.NullInt64
+		if err := typeRows.Scan(&id, &mg); err != nil {
+			return nil, err
+		}
+		if mg.Valid {
+			// A type without an amount gets no estimate rather than 0 mg
+			model.TypeMg[id] = int(mg.Int64)
+		}
+	}
+	if err := typeRows.Err(); err != nil {
+		return nil, err
+	}
+
+	sizeRows, err := r.db.QueryContext(ctx, `SELECT id, caffeine_factor FROM coffee_sizes`)
+	if err != nil {
+		return nil, err
+	}
+	defer sizeRows.Close()
+	for sizeRows.Next() {
+		var id int
+		var factor float64
+		if err := sizeRows.Scan(&id, &factor); err != nil {
+			return nil, err
+		}
+		model.SizeFactors[id] = factor
+	}
+	if err := sizeRows.Err(); err != nil {
+		return nil, err
+	}
+
+	r.cache.Set(caffeineModelCacheKey, model, cache.DefaultExpiration)
+
+	return model, nil
+}
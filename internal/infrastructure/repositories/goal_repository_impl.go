@@ -0,0 +1,85 @@
+// file: internal/infrastructure/repositories/goal_repository_impl.go
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type GoalRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewGoalRepositoryImpl(db *sql.DB) repositories.GoalRepository {
+	return &GoalRepositoryImpl{db: db}
+}
+
+// Upsert inserts the goal, or updates the target when the user already has a goal of this type.
+// The stored id and created_at are written back to the entity.
+func (r *GoalRepositoryImpl) Upsert(ctx context.Context, goal *entities.Goal) error {
+	query := `
+		INSERT INTO user_goals (id, user_id, goal_type, target, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, goal_type)
+		DO UPDATE SET
+			target = EXCLUDED.target,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`
+	return r.db.QueryRowContext(ctx, query,
+		goal.ID,
+		goal.UserID,
+		goal.Type,
+		goal.Target,
+		goal.CreatedAt,
+		goal.UpdatedAt,
+	).Scan(&goal.ID, &goal.CreatedAt)
+}
+
+// GetByUserID returns the user's goals, oldest first
+func (r *GoalRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Goal, error) {
+	query := `
+		SELECT id, user_id, goal_type, target, created_at, updated_at
+		FROM user_goals
+		WHERE user_id = $1
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	goals := make([]*entities.Goal, 0)
+	for rows.Next() {
+		var goal entities.Goal
+		if err := rows.Scan(&goal.ID, &goal.UserID, &goal.Type, &goal.Target, &goal.CreatedAt, &goal.UpdatedAt); err != nil {
+			return nil, err
+		}
+		goals = append(goals, &goal)
+	}
+
+	return goals, rows.Err()
+}
+
+// Delete removes one of the user's goals
+func (r *GoalRepositoryImpl) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	query := `DELETE FROM user_goals WHERE id = $1 AND user_id = $2`
+	res, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
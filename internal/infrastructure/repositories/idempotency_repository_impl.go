@@ -0,0 +1,111 @@
+// file: internal/infrastructure/repositories/idempotency_repository_impl.go
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type IdempotencyRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepositoryImpl(db *sql.DB) repositories.IdempotencyRepository {
+	return &IdempotencyRepositoryImpl{db: db}
+}
+
+func (r *IdempotencyRepositoryImpl) Reserve(ctx context.Context, record *entities.IdempotencyRecord, staleBefore time.Time) (*entities.IdempotencyRecord, bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (user_id, key, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+			status_code = NULL,
+			content_type = NULL,
+			response_body = NULL,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+			OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < $6)
+		RETURNING key
+	`
+	var key string
+	err := r.db.QueryRowContext(ctx, query,
+		record.UserID,
+		record.Key,
+		record.RequestHash,
+		record.CreatedAt,
+		record.ExpiresAt,
+		staleBefore,
+	).Scan(&key)
+	if err == nil {
+		return nil, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, err
+	}
+
+	// The key is held by a live record
+	existing, err := r.get(ctx, record.UserID, record.Key)
+	if err != nil {
+		return nil, false, err
+	}
+	return existing, false, nil
+}
+
+func (r *IdempotencyRepositoryImpl) get(ctx context.Context, userID uuid.UUID, key string) (*entities.IdempotencyRecord, error) {
+	query := `
+		SELECT user_id, key, request_hash, status_code, COALESCE(content_type, ''), response_body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND key = $2
+	`
+	var record entities.IdempotencyRecord
+	err := r.db.QueryRowContext(ctx, query, userID, key).Scan(
+		&record.UserID,
+		&record.Key,
+		&record.RequestHash,
+		&record.StatusCode,
+		&record.ContentType,
+		&record.ResponseBody,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *IdempotencyRepositoryImpl) Complete(ctx context.Context, userID uuid.UUID, key string, statusCode int, contentType string, body []byte) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $3, content_type = $4, response_body = $5
+		WHERE user_id = $1 AND key = $2
+	`
+	_, err := r.db.ExecContext(ctx, query, userID, key, statusCode, contentType, body)
+	return err
+}
+
+func (r *IdempotencyRepositoryImpl) Release(ctx context.Context, userID uuid.UUID, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2 AND status_code IS NULL`
+	_, err := r.db.ExecContext(ctx, query, userID, key)
+	return err
+}
+
+func (r *IdempotencyRepositoryImpl) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM idempotency_keys WHERE expires_at <= $1`
+	res, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
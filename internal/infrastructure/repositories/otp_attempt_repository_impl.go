@@ -0,0 +1,75 @@
+// file: internal/infrastructure/repositories/otp_attempt_repository_impl.go
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"coffee-tracker-backend/internal/repositories"
+)
+
+type OtpAttemptRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewOtpAttemptRepositoryImpl(db *sql.DB) repositories.OtpAttemptRepository {
+	return &OtpAttemptRepositoryImpl{db: db}
+}
+
+// Reserve increments the counter and reads it back in one statement, so each of concurrent guesses
+// gets its own count. A counter last updated before windowStart starts over. The row is locked
+// first, so of concurrent attempts only the first one sees the ended lockout.
+func (r *OtpAttemptRepositoryImpl) Reserve(ctx context.Context, key string, windowStart, now time.Time) (int, time.Time, bool, error) {
+	query := `
+		WITH prev AS (
+			SELECT locked_until FROM otp_attempts WHERE key = $1 FOR UPDATE
+		)
+		INSERT INTO otp_attempts (key, failures, updated_at)
+		VALUES ($1, 1, $3)
+		ON CONFLICT (key) DO UPDATE
+		SET failures = CASE
+				WHEN otp_attempts.locked_until > $3 THEN otp_attempts.failures
+				WHEN otp_attempts.updated_at < $2 THEN 1
+				ELSE otp_attempts.failures + 1
+			END,
+			updated_at = CASE WHEN otp_attempts.locked_until > $3 THEN otp_attempts.updated_at ELSE EXCLUDED.updated_at END,
+			locked_until = CASE WHEN otp_attempts.locked_until <= $3 THEN NULL ELSE otp_attempts.locked_until END
+		RETURNING failures, locked_until, COALESCE((SELECT locked_until <= $3 FROM prev), false)
+	`
+	var failures int
+	var lockedUntil sql.NullTime
+	var lockEnded bool
+	err := r.db.QueryRowContext(ctx, query, key, windowStart, now).Scan(&failures, &lockedUntil, &lockEnded)
+	return failures, lockedUntil.Time, lockEnded, err
+}
+
+func (r *OtpAttemptRepositoryImpl) Release(ctx context.Context, key string) error {
+	query := `UPDATE otp_attempts SET failures = failures - 1 WHERE key = $1 AND failures > 0`
+	_, err := r.db.ExecContext(ctx, query, key)
+	return err
+}
+
+func (r *OtpAttemptRepositoryImpl) Lock(ctx context.Context, key string, until time.Time) error {
+	query := `UPDATE otp_attempts SET locked_until = GREATEST(COALESCE(locked_until, $2), $2) WHERE key = $1`
+	_, err := r.db.ExecContext(ctx, query, key, until)
+	return err
+}
+
+func (r *OtpAttemptRepositoryImpl) Reset(ctx context.Context, key string) error {
+	query := `DELETE FROM otp_attempts WHERE key = $1`
+	_, err := r.db.ExecContext(ctx, query, key)
+	return err
+}
+
+func (r *OtpAttemptRepositoryImpl) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM otp_attempts
+		WHERE updated_at < $1 AND (locked_until IS NULL OR locked_until < $1)
+	`
+	res, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"coffee-tracker-backend/internal/entities"
 	"coffee-tracker-backend/internal/infrastructure/http/models"
@@ -24,39 +25,73 @@ func NewUserRepositoryImpl(db *sql.DB) repositories.UserRepository {
 	return &UserRepositoryImpl{db: db}
 }
 
-// Create inserts a new user
+// Create inserts a new user with the given status and their default settings, in one transaction
 func (r *UserRepositoryImpl) Create(ctx context.Context, user *entities.User) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO users (id, email, mobile, name, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (id, email, mobile, name, status_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		user.ID,
 		utils.SafeToLower(user.Email),
 		utils.NullIfEmpty(user.Mobile),
 		utils.NullIfEmpty(user.Name),
+		user.StatusID,
 		user.CreatedAt,
 		user.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
-	return nil
+
+	columns := []string{"user_id", "created_at", "updated_at"}
+	params := []interface{}{user.ID, user.CreatedAt, user.CreatedAt}
+	for _, setting := range entities.AllSettings {
+		columns = append(columns, setting.ColumnName())
+		params = append(params, setting.DefaultValue())
+	}
+	placeholders := make([]string, len(params))
+	for i := range params {
+		placeholders[i] = "$" + strconv.Itoa(i+1)
+	}
+	query = fmt.Sprintf(`INSERT INTO user_settings (%s) VALUES (%s)`, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+	if _, err := tx.ExecContext(ctx, query, params...); err != nil {
+		return fmt.Errorf("failed to create settings of user %s: %w", user.ID, err)
+	}
+
+	return tx.Commit()
+}
+
+// userColumns are the columns read by scanUser; a user may have no email, mobile or name yet
+const userColumns = `id, COALESCE(email, '') AS email, COALESCE(mobile, '') AS mobile, COALESCE(name, '') AS name,
+	COALESCE(avatar_url, '') AS avatar_url, status_id, created_at, updated_at, deletion_scheduled_at`
+
+func scanUser(row interface{ Scan(dest ...any) error }, user *entities.User) error {
+	return row.Scan(
+		&user.ID, &user.Email, &user.Mobile, &user.Name,
+		&user.AvatarURL, &user.StatusID, &user.CreatedAt, &user.UpdatedAt, &user.DeletionScheduledAt,
+	)
 }
 
 // getUserByField is a helper for fetching users by any field
 func (r *UserRepositoryImpl) getUserByField(ctx context.Context, field string, value interface{}) (*entities.User, error) {
 	query := fmt.Sprintf(`
-		SELECT id, email, mobile, name, COALESCE(avatar_url, '') AS avatar_url, status_id, created_at, updated_at
+		SELECT %s
 		FROM users
 		WHERE %s = $1
-	`, field)
+	`, userColumns, field)
 
 	var user entities.User
-	err := r.db.QueryRowContext(ctx, query, value).Scan(
-		&user.ID, &user.Email, &user.Mobile, &user.Name,
-		&user.AvatarURL, &user.StatusID, &user.CreatedAt, &user.UpdatedAt,
-	)
+	err := scanUser(r.db.QueryRowContext(ctx, query, value), &user)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found by %s=%v: %w", field, value, repositories.ErrNotFound)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("user not found by %s=%v: %w", field, value, err)
 	}
@@ -98,16 +133,101 @@ func (r *UserRepositoryImpl) Update(ctx context.Context, user *entities.User) er
 	return nil
 }
 
-// Delete removes a user by ID
+// Activate promotes a pending user to active
+func (r *UserRepositoryImpl) Activate(ctx context.Context, id uuid.UUID) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET status_id = $2, updated_at = $4 WHERE id = $1 AND status_id = $3`,
+		id, entities.StatusActive, entities.StatusPending, utils.NowUTC(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to activate user %s: %w", id, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// userDataTables hold rows of a user that are not removed by a foreign key cascade
+var userDataTables = []string{"coffee_entries", "user_settings", "user_otps", "user_refresh_tokens"}
+
+// Delete removes a user by ID, together with their data, in one transaction
 func (r *UserRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM users WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to delete user %s: %w", id, err)
 	}
+	defer tx.Rollback()
+
+	for _, table := range userDataTables {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE user_id = $1`, id); err != nil {
+			return fmt.Errorf("failed to delete %s of user %s: %w", table, id, err)
+		}
+	}
+	// Goals, tombstones, idempotency keys and data exports cascade
+	if _, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete user %s: %w", id, err)
+	}
+	return tx.Commit()
+}
+
+// ScheduleDeletion marks the user deleted until purgeAt
+func (r *UserRepositoryImpl) ScheduleDeletion(ctx context.Context, id uuid.UUID, purgeAt time.Time) error {
+	query := `UPDATE users SET status_id = $2, deletion_scheduled_at = $3, updated_at = $4 WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, entities.StatusDeleted, purgeAt, utils.NowUTC())
+	if err != nil {
+		return fmt.Errorf("failed to schedule deletion of user %s: %w", id, err)
+	}
 	return nil
 }
 
+// CancelDeletion reactivates a deleted user within the grace period
+func (r *UserRepositoryImpl) CancelDeletion(ctx context.Context, id uuid.UUID) (bool, error) {
+	now := utils.NowUTC()
+	query := `
+		UPDATE users
+		SET status_id = $2, deletion_scheduled_at = NULL, updated_at = $4
+		WHERE id = $1 AND status_id = $3 AND deletion_scheduled_at > $4
+	`
+	res, err := r.db.ExecContext(ctx, query, id, entities.StatusActive, entities.StatusDeleted, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel deletion of user %s: %w", id, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// ListDueForDeletion returns the deleted users whose grace period ended before the given time, oldest first
+func (r *UserRepositoryImpl) ListDueForDeletion(ctx context.Context, before time.Time, limit int) ([]*entities.User, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM users
+		WHERE status_id = $1 AND deletion_scheduled_at <= $2
+		ORDER BY deletion_scheduled_at
+		LIMIT $3
+	`, userColumns)
+
+	rows, err := r.db.QueryContext(ctx, query, entities.StatusDeleted, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users due for deletion: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*entities.User
+	for rows.Next() {
+		var user entities.User
+		if err := scanUser(rows, &user); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+	return users, rows.Err()
+}
+
 // UpdateProfile updates user profile fields based on request DTO
 func (r *UserRepositoryImpl) UpdateProfile(ctx context.Context, userID uuid.UUID, req *models.UpdateUserProfileRequest) error {
 	query := `UPDATE users SET `
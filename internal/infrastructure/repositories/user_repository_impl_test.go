@@ -0,0 +1,83 @@
+// file: internal/infrastructure/repositories/user_repository_impl_test.go
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+)
+
+// testDB connects to the database in TEST_DATABASE_URL (a migrated schema), or skips the test
+func testDB(t *testing.T) *UserRepositoryImpl {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := database.NewSupabaseDB(url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return &UserRepositoryImpl{db: db}
+}
+
+func TestUserRepositoryRegisterAndActivate(t *testing.T) {
+	repo := testDB(t)
+	ctx := context.Background()
+
+	// A sign-up only knows the mobile number: email and name stay NULL
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	user := &entities.User{
+		ID:        uuid.New(),
+		Mobile:    fmt.Sprintf("+9990%09d", rand.Intn(1e9)),
+		StatusID:  entities.StatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	t.Cleanup(func() { repo.Delete(context.Background(), user.ID) })
+
+	got, err := repo.GetByMobile(ctx, user.Mobile)
+	if err != nil {
+		t.Fatalf("GetByMobile: %v", err)
+	}
+	if got.ID != user.ID || got.Email != "" || got.Name != "" || got.StatusID != entities.StatusPending {
+		t.Fatalf("GetByMobile = %+v, want the pending user %s without email and name", got, user.ID)
+	}
+
+	var settings int
+	if err := repo.db.QueryRowContext(ctx, `SELECT count(*) FROM user_settings WHERE user_id = $1`, user.ID).Scan(&settings); err != nil {
+		t.Fatalf("count settings: %v", err)
+	}
+	if settings != 1 {
+		t.Fatalf("user has %d settings rows, want 1 created with the user", settings)
+	}
+
+	activated, err := repo.Activate(ctx, user.ID)
+	if err != nil || !activated {
+		t.Fatalf("Activate = %v, %v; want true, nil", activated, err)
+	}
+	got, err = repo.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if !got.IsActive() {
+		t.Fatalf("status after Activate = %d, want active", got.StatusID)
+	}
+
+	// Only a pending user is activated
+	if activated, err := repo.Activate(ctx, user.ID); err != nil || activated {
+		t.Fatalf("second Activate = %v, %v; want false, nil", activated, err)
+	}
+}
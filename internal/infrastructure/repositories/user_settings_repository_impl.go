@@ -23,14 +23,17 @@ func NewUserSettingsRepositoryImpl(db *sql.DB) repositories.UserSettingsReposito
 // Get returns all user settings as a struct
 func (r *UserSettingsRepositoryImpl) Get(ctx context.Context, userID uuid.UUID) (*entities.UserSettings, error) {
     query := `
-        SELECT user_id, biometric_enabled, dark_mode, notifications_enabled, created_at, updated_at
+        SELECT user_id, biometric_enabled, dark_mode, notifications_enabled, caffeine_half_life_minutes, daily_caffeine_limit_mg, daily_cup_limit, created_at, updated_at
         FROM user_settings
         WHERE user_id = $1
     `
     row := r.db.QueryRowContext(ctx, query, userID)
 
     var s entities.UserSettings
-    if err := row.Scan(&s.UserID, &s.BiometricEnabled, &s.DarkMode, &s.NotificationsEnabled, &s.CreatedAt, &s.UpdatedAt); err != nil {
+    if err := row.Scan(&s.UserID, &s.BiometricEnabled, &s.DarkMode, &s.NotificationsEnabled, &s.CaffeineHalfLifeMinutes, &s.DailyCaffeineLimitMg, &s.DailyCupLimit, &s.CreatedAt, &s.UpdatedAt); err != nil {
+        if err == sql.ErrNoRows {
+            return nil, repositories.ErrNotFound
+        }
         return nil, err
     }
 
@@ -55,7 +58,7 @@ func (r *UserSettingsRepositoryImpl) Reset(ctx context.Context, userID uuid.UUID
 	if column == "" {
 		return fmt.Errorf("unknown setting: %d", setting)
 	}
-    query := fmt.Sprintf(`UPDATE user_settings SET %s = false, updated_at = $2 WHERE user_id = $1`, column)
-    _, err := r.db.ExecContext(ctx, query, userID, utils.NowUTC())
+    query := fmt.Sprintf(`UPDATE user_settings SET %s = $3, updated_at = $2 WHERE user_id = $1`, column)
+    _, err := r.db.ExecContext(ctx, query, userID, utils.NowUTC(), setting.DefaultValue())
     return err
 }
@@ -11,5 +11,10 @@ type StorageService interface {
 	//ListBuckets(ctx context.Context) ([]map[string]any, error)
 	//CreateBucket(ctx context.Context, name string, isPublic bool) error
 	UploadFile(ctx context.Context, bucket, filename string, file io.Reader, imagesOnly bool) (string, error)
-	//GenerateSignedURL(ctx context.Context, bucket, filename string, expiresInSeconds int) (string, error)
+	// DownloadFile opens an object for reading; the caller closes it
+	DownloadFile(ctx context.Context, bucket, filename string) (io.ReadCloser, error)
+	// GenerateSignedURL returns a temporary download URL for an object of a private bucket
+	GenerateSignedURL(ctx context.Context, bucket, filename string, expiresInSeconds int) (string, error)
+	// DeleteFiles removes objects from a bucket; objects that do not exist are ignored
+	DeleteFiles(ctx context.Context, bucket string, filenames ...string) error
 }
\ No newline at end of file
@@ -4,12 +4,14 @@ package storage
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"mime"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"path/filepath"
 	"strings"
 )
@@ -99,9 +101,12 @@ func (s *SupabaseStorageService) UploadFile(ctx context.Context, bucket, filenam
 	// Get MIME type from filename extension
 	mimeType := mime.TypeByExtension(filepath.Ext(filename))
 	// Validate MIME type
-	if imagesOnly && mimeType == "" || !strings.HasPrefix(mimeType, "image/") {
+	if imagesOnly && (mimeType == "" || !strings.HasPrefix(mimeType, "image/")) {
 		return "", fmt.Errorf("invalid file type for %s: MIME type %s is not an image", filename, mimeType)
 	}
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
 
 	// Create form file with explicit MIME type
 	part, err := writer.CreatePart(map[string][]string{
@@ -148,15 +153,70 @@ func (s *SupabaseStorageService) UploadFile(ctx context.Context, bucket, filenam
 	return publicURL, nil
 }
 
+// DownloadFile opens an object through the authenticated endpoint, so it works for private buckets too
+func (s *SupabaseStorageService) DownloadFile(ctx context.Context, bucket, filename string) (io.ReadCloser, error) {
+	downloadURL := fmt.Sprintf("%s/object/%s/%s", s.storageURL, bucket, filename)
+	req, err := http.NewRequestWithContext(ctx, "GET", downloadURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %v", downloadURL, err)
+	}
+	s.addAuthHeaders(req)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request for %s: %v", downloadURL, err)
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("download failed for %s: %s", filename, string(body))
+	}
+
+	return resp.Body, nil
+}
+
+// DeleteFiles removes objects in one request; Supabase skips the names it does not find
+func (s *SupabaseStorageService) DeleteFiles(ctx context.Context, bucket string, filenames ...string) error {
+	if len(filenames) == 0 {
+		return nil
+	}
+
+	data, _ := json.Marshal(map[string]any{"prefixes": filenames})
+	deleteURL := fmt.Sprintf("%s/object/%s", s.storageURL, bucket)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", deleteURL, bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request for %s: %v", deleteURL, err)
+	}
+	s.addAuthHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request for %s: %v", deleteURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("delete failed in %s: %s", bucket, string(body))
+	}
+	return nil
+}
+
 // ---------------- Signed URL ----------------
-/*
+
 // GenerateSignedURL creates a temporary signed URL for accessing a private file
 func (s *SupabaseStorageService) GenerateSignedURL(ctx context.Context, bucket, filename string, expiresInSeconds int) (string, error) {
 	payload := map[string]any{
 		"expiresIn": expiresInSeconds,
 	}
 	data, _ := json.Marshal(payload)
-	escapedFilename := url.PathEscape(filename)
+	// Escape each segment, keeping the folder separators
+	segments := strings.Split(filename, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	escapedFilename := strings.Join(segments, "/")
 	req, err := http.NewRequestWithContext(ctx,
 		"POST",
 		fmt.Sprintf("%s/object/sign/%s/%s", s.storageURL, bucket, escapedFilename),
@@ -187,13 +247,17 @@ func (s *SupabaseStorageService) GenerateSignedURL(ctx context.Context, bucket,
 		return "", fmt.Errorf("failed to decode response for %s: %v", filename, err)
 	}
 
+	if result.SignedURL == "" {
+		return "", fmt.Errorf("signed URL failed for %s: empty signedURL", filename)
+	}
+
 	// Ensure the signed URL is absolute
 	if result.SignedURL[0] == '/' {
 		return s.storageURL + result.SignedURL, nil
 	}
 	return fmt.Sprintf("%s/%s", s.storageURL, result.SignedURL), nil
 }
-*/
+
 // ---------------- Helpers ----------------
 
 // addAuthHeaders adds authentication headers to the request
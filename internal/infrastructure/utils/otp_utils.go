@@ -10,20 +10,12 @@ import (
 	"coffee-tracker-backend/internal/infrastructure/config"
 )
 
-// GenerateOTP generates a 6-digit OTP based on strength
+// GenerateOTP generates a 6-digit OTP based on strength.
+// Both strengths draw every code from 000000 to 999999 with equal probability.
 func GenerateOTP(strength config.OtpStrength) (string, error) {
 	switch strength {
-	case config.OTP_EASY:
-		// easy: 6-digit numeric, deterministic enough for "easy"
-		n, err := randIntEasy(100000, 999999)
-		if err != nil {
-			return "", err
-		}
-		return fmt.Sprintf("%06d", n), nil
-
-	case config.OTP_STRONG:
-		// strong: 6-digit numeric using crypto/rand
-		n, err := randIntCrypto(100000, 999999)
+	case config.OTP_EASY, config.OTP_STRONG:
+		n, err := randIntCrypto(0, 999999)
 		if err != nil {
 			return "", err
 		}
@@ -34,17 +26,7 @@ func GenerateOTP(strength config.OtpStrength) (string, error) {
 	}
 }
 
-// randIntEasy: simple "easy" OTP generator
-func randIntEasy(min, max int) (int, error) {
-	b := make([]byte, 1)
-	_, err := rand.Read(b)
-	if err != nil {
-		return 0, err
-	}
-	return min + int(b[0])%(max-min+1), nil
-}
-
-// randIntCrypto: secure crypto-based random integer
+// randIntCrypto: secure crypto-based random integer, uniform in [min, max]
 func randIntCrypto(min, max int) (int, error) {
 	diff := int64(max - min + 1)
 	n, err := rand.Int(rand.Reader, big.NewInt(diff))
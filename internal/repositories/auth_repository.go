@@ -5,6 +5,8 @@ import (
 	"context"
 	"time"
 
+	"coffee-tracker-backend/internal/entities"
+
 	"github.com/google/uuid"
 )
 
@@ -20,6 +22,9 @@ type AuthRepository interface {
 	// InvalidateOTP marks an OTP as invalid, preventing its future use.
 	InvalidateOTP(ctx context.Context, userID uuid.UUID, otp string) error
 
+	// InvalidateUserOTPs marks all the unused OTPs of a user as invalid.
+	InvalidateUserOTPs(ctx context.Context, userID uuid.UUID) error
+
 	// SaveRefreshToken inserts or replaces the refresh token for a user
 	SaveRefreshToken(ctx context.Context, userID uuid.UUID, deviceID uuid.UUID, token string, expiresAt time.Time) error
 
@@ -34,4 +39,7 @@ type AuthRepository interface {
 
 	// InvalidateAllUserTokens marks all users OTP as invalid,
 	InvalidateAllUserTokens(ctx context.Context, userID uuid.UUID) error
+
+	// ListDeviceSessions returns the devices holding an unexpired refresh token, most recently used first
+	ListDeviceSessions(ctx context.Context, userID uuid.UUID) ([]entities.DeviceSession, error)
 }
\ No newline at end of file
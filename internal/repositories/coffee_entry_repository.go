@@ -10,14 +10,114 @@ import (
 	"github.com/google/uuid"
 )
 
+// CoffeeEntrySort is the order of a coffee entry listing; ties are broken by id
+type CoffeeEntrySort string
+
+const (
+	SortTimestampAsc  CoffeeEntrySort = "timestamp"
+	SortTimestampDesc CoffeeEntrySort = "-timestamp"
+	SortCaffeineAsc   CoffeeEntrySort = "caffeine"
+	SortCaffeineDesc  CoffeeEntrySort = "-caffeine"
+	SortRatingAsc     CoffeeEntrySort = "rating"
+	SortRatingDesc    CoffeeEntrySort = "-rating"
+)
+
+func (s CoffeeEntrySort) IsValid() bool {
+	switch s {
+	case SortTimestampAsc, SortTimestampDesc, SortCaffeineAsc, SortCaffeineDesc, SortRatingAsc, SortRatingDesc:
+		return true
+	}
+	return false
+}
+
+// CoffeeEntryCursor is the keyset position of the last entry of a page, in (timestamp, id) order
+type CoffeeEntryCursor struct {
+	Timestamp time.Time
+	ID        uuid.UUID
+}
+
+// CoffeeEntryFilter selects a page of a user's entries. Nil fields are not filtered on.
+type CoffeeEntryFilter struct {
+	UserID       uuid.UUID
+	From         *time.Time // inclusive
+	To           *time.Time // exclusive
+	CoffeeTypeID *int
+	SizeID       *int
+	HasNotes     *bool
+	HasLocation  *bool
+	Sort         CoffeeEntrySort    // defaults to SortTimestampAsc
+	After        *CoffeeEntryCursor // keyset pagination, only with the timestamp sorts
+	Limit        int
+	Offset       int
+}
+
 type CoffeeEntryRepository interface {
+	// WithinTx runs fn with a repository bound to one transaction, committed when fn returns nil
+	WithinTx(ctx context.Context, fn func(tx CoffeeEntryRepository) error) error
+	// LockUser serializes writers of the user's entries until the current transaction ends (WithinTx only).
+	// Every write assigning a version takes it too, so holding it also shows a settled change feed.
+	LockUser(ctx context.Context, userID uuid.UUID) error
 	Create(ctx context.Context, entry *entities.CoffeeEntry) error
+	// CreateMany inserts entries of the given user in bulk, e.g. for an import; run it within a
+	// transaction, several statements may be needed
+	CreateMany(ctx context.Context, userID uuid.UUID, entries []*entities.CoffeeEntry) error
+	// Update returns ErrNotFound when entry.UserID has no entry with entry.ID
 	Update(ctx context.Context, entry *entities.CoffeeEntry) error
+	// Replace is Update of every client-editable field, the location included (sync); entry is
+	// refreshed with the stored state
+	Replace(ctx context.Context, entry *entities.CoffeeEntry) error
+	// GetByID returns ErrNotFound when no entry has the id
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.CoffeeEntry, error)
-	GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.CoffeeEntry, error)
-	GetByUserIDAndDateRange(ctx context.Context, userID uuid.UUID, limit int, offset int, startDate, endDate time.Time) ([]*entities.CoffeeEntry, error)
+	// GetOwner returns the user an entry id belongs to, trashed and purged entries included;
+	// ErrNotFound when the id was never used
+	GetOwner(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	// List returns the user's entries matching every set field of the filter
+	List(ctx context.Context, filter CoffeeEntryFilter) ([]*entities.CoffeeEntry, error)
+	// Delete moves the entry to the trash (soft delete); returns ErrNotFound when the user has no such entry.
+	// Entries in the trash are left out of every other read.
 	Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
-	DeleteAll(ctx context.Context, userID uuid.UUID) error
-	GetStats(ctx context.Context, userID uuid.UUID) (*entities.CoffeeStats, error)
+	// DeleteAt is Delete with the time of the delete given by the caller (e.g. an offline client). That
+	// time is the entry's updated_at and the tombstone's DeletedAt; the trash keeps the server time.
+	DeleteAt(ctx context.Context, id uuid.UUID, userID uuid.UUID, deletedAt time.Time) error
+	// DeleteAll moves the user's entries with a timestamp in [from, to) to the trash and returns how many were moved.
+	// A nil bound leaves that side of the range open, so with both nil every entry is deleted.
+	DeleteAll(ctx context.Context, userID uuid.UUID, from, to *time.Time) (int64, error)
+	// ListDeleted returns the user's trash, most recently deleted first
+	ListDeleted(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.CoffeeEntry, error)
+	// Restore takes an entry out of the trash; returns ErrNotFound when it is not in the user's trash
+	Restore(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*entities.CoffeeEntry, error)
+	// PurgeDeleted permanently removes all users' entries deleted before the given time, leaving tombstones
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	// GetChangedSince returns the user's entries created or updated after the given version, oldest change first
+	GetChangedSince(ctx context.Context, userID uuid.UUID, version int64, limit int) ([]*entities.CoffeeEntry, error)
+	// GetTombstonesSince returns the user's deletes (trashed or purged entries) after the given version, oldest first
+	GetTombstonesSince(ctx context.Context, userID uuid.UUID, version int64, limit int) ([]entities.EntryTombstone, error)
+	// GetTombstone returns ErrNotFound when the user has no tombstone for the id
+	GetTombstone(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*entities.EntryTombstone, error)
+	// DeleteTombstone forgets the delete of a purged entry
+	DeleteTombstone(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	// StreamEntries calls fn for each of the user's entries with a timestamp in [from, to), oldest first,
+	// one row at a time; nil bounds are open. An error from fn stops the stream and is returned.
+	StreamEntries(ctx context.Context, userID uuid.UUID, from, to *time.Time, fn func(*entities.CoffeeEntry) error) error
+	// SearchNotes returns the user's entries whose notes match a web-style search query, best match first
+	SearchNotes(ctx context.Context, userID uuid.UUID, query string, limit int) ([]entities.EntrySearchResult, error)
+	// GetStats returns the user's totals; type/size names are localized to languageCode
+	GetStats(ctx context.Context, userID uuid.UUID, languageCode string) (*entities.CoffeeStats, error)
 	GetCount(ctx context.Context, userID uuid.UUID) (int, error)
+	// GetCountInRange returns the number of entries with a timestamp in [from, to); nil bounds are open
+	GetCountInRange(ctx context.Context, userID uuid.UUID, from, to *time.Time) (int, error)
+	// GetCaffeineTotal returns the caffeine sum (mg) and entry count in [startDate, endDate)
+	GetCaffeineTotal(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) (totalCaffeine int, entries int, err error)
+	// GetCaffeineIntakes returns the entries with known caffeine in [startDate, endDate), oldest first
+	GetCaffeineIntakes(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time) ([]entities.CaffeineIntake, error)
+	// GetSeries groups entries in [startDate, endDate) into buckets aligned to the given IANA timezone.
+	// Empty buckets are not returned.
+	GetSeries(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, bucket entities.StatsBucket, timezone string) ([]entities.SeriesPoint, error)
+	// GetHeatmap counts entries in [startDate, endDate) per local weekday and hour. Empty cells are not returned.
+	GetHeatmap(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, timezone string) ([]entities.HeatmapCell, error)
+	// GetDailyCounts counts entries in [startDate, endDate) per local day. Days without entries are not returned.
+	GetDailyCounts(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, timezone string) ([]entities.DailyCount, error)
+	// GetSpending sums the prices in [startDate, endDate) per bucket and currency, like GetSeries.
+	// Entries without a price and empty buckets are not returned.
+	GetSpending(ctx context.Context, userID uuid.UUID, startDate, endDate time.Time, bucket entities.StatsBucket, timezone string) ([]entities.SpendingPeriod, error)
 }
@@ -0,0 +1,15 @@
+// file: internal/repositories/consumed_token_repository.go
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// ConsumedTokenRepository remembers the single-use tokens already redeemed, by their jti
+type ConsumedTokenRepository interface {
+	// Consume marks the token used and returns false when it already was
+	Consume(ctx context.Context, jti string, expiresAt time.Time) (bool, error)
+	// DeleteExpired removes the tokens expired before the given time and returns how many were removed
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,23 @@
+// file: internal/repositories/data_export_repository.go
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+type DataExportRepository interface {
+	// CreatePending records a pending export unless the user already has one started after staleBefore;
+	// older pending exports are marked failed. Returns false when the export was not created.
+	CreatePending(ctx context.Context, export *entities.DataExport, staleBefore time.Time) (bool, error)
+	// GetByID returns ErrNotFound when the user has no export with the id
+	GetByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*entities.DataExport, error)
+	MarkReady(ctx context.Context, id uuid.UUID, objectPath string) error
+	MarkFailed(ctx context.Context, id uuid.UUID, message string) error
+	// ListObjectPaths returns the storage paths of the user's uploaded archives
+	ListObjectPaths(ctx context.Context, userID uuid.UUID) ([]string, error)
+}
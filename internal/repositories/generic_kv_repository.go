@@ -6,7 +6,14 @@ import (
 	"context"
 )
 
+// KV lookup types accepted by GetKV
+const (
+	KVCoffeeTypes = 1
+	KVCoffeeSizes = 2
+)
 
 type GenericKVRepository interface {
 	GetKV(ctx context.Context, typeID int, languageCode string) ([]entities.KVItem, error)
+	// GetCaffeineModel returns the caffeine estimates attached to the coffee type/size lookups
+	GetCaffeineModel(ctx context.Context) (*entities.CaffeineModel, error)
 }
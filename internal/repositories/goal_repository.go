@@ -0,0 +1,19 @@
+// file: internal/repositories/goal_repository.go
+package repositories
+
+import (
+	"context"
+
+	"coffee-tracker-backend/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+// GoalRepository stores habit goals, at most one per goal type and user
+type GoalRepository interface {
+	// Upsert creates the goal or updates the target of the user's existing goal of the same type
+	Upsert(ctx context.Context, goal *entities.Goal) error
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Goal, error)
+	// Delete returns ErrNotFound when the user has no such goal
+	Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+}
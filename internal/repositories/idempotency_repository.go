@@ -0,0 +1,24 @@
+// file: internal/repositories/idempotency_repository.go
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+
+	"github.com/google/uuid"
+)
+
+type IdempotencyRepository interface {
+	// Reserve stores a new in-progress record. If the key is already taken by a live record, nothing is
+	// stored and that record is returned with reserved=false. Expired records, and in-progress records
+	// created before staleBefore (abandoned by a crashed request), are taken over.
+	Reserve(ctx context.Context, record *entities.IdempotencyRecord, staleBefore time.Time) (existing *entities.IdempotencyRecord, reserved bool, err error)
+	// Complete stores the response of a reserved key
+	Complete(ctx context.Context, userID uuid.UUID, key string, statusCode int, contentType string, body []byte) error
+	// Release drops a reserved key so the request can be retried
+	Release(ctx context.Context, userID uuid.UUID, key string) error
+	// DeleteExpired removes the records expired before the given time and returns how many were removed
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,23 @@
+// file: internal/repositories/otp_attempt_repository.go
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// OtpAttemptRepository counts failed OTP verifications per key (a user or a client IP)
+type OtpAttemptRepository interface {
+	// Reserve counts an attempt as a failure until it is released, and returns the failures of the key
+	// since windowStart (this one included) with the end of its lockout. A key locked out at now is
+	// returned unchanged. A lockout over by now is cleared, and reported (true) to this attempt only.
+	Reserve(ctx context.Context, key string, windowStart, now time.Time) (int, time.Time, bool, error)
+	// Release uncounts a reserved attempt that succeeded
+	Release(ctx context.Context, key string) error
+	// Lock locks the key out until the given time, or keeps a later lockout
+	Lock(ctx context.Context, key string, until time.Time) error
+	// Reset forgets the failures and the lockout of the key
+	Reset(ctx context.Context, key string) error
+	// DeleteExpired removes the keys neither failed nor locked out since the given time and returns how many were removed
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
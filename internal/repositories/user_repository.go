@@ -3,6 +3,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"coffee-tracker-backend/internal/entities"
 	"coffee-tracker-backend/internal/infrastructure/http/models"
@@ -11,14 +12,24 @@ import (
 )
 
 type UserRepository interface {
+	// Create inserts the user together with their default settings
 	Create(ctx context.Context, user *entities.User) error
+	// GetByID, GetByMobile and GetByEmail wrap ErrNotFound when there is no such user
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error)
 	GetByMobile(ctx context.Context, mobile string) (*entities.User, error)
 	GetByEmail(ctx context.Context, email string) (*entities.User, error)
 	Update(ctx context.Context, user *entities.User) error
+	// Activate promotes a pending user to StatusActive; it reports whether the user was pending
+	Activate(ctx context.Context, id uuid.UUID) (bool, error)
+	// Delete permanently removes the user with their entries, settings, OTPs and refresh tokens
 	Delete(ctx context.Context, id uuid.UUID) error
+	// ScheduleDeletion moves the user to StatusDeleted until purgeAt
+	ScheduleDeletion(ctx context.Context, id uuid.UUID, purgeAt time.Time) error
+	// CancelDeletion restores a deleted user whose purge time has not come yet; it reports whether one was restored
+	CancelDeletion(ctx context.Context, id uuid.UUID) (bool, error)
+	// ListDueForDeletion returns up to limit deleted users whose purge time is before the given time
+	ListDueForDeletion(ctx context.Context, before time.Time, limit int) ([]*entities.User, error)
 	UpdateProfile(ctx context.Context, userID uuid.UUID, req *models.UpdateUserProfileRequest) error
 	UpdateProfileImage(ctx context.Context, user *entities.User) error
 	DeleteProfileImage(ctx context.Context, userID uuid.UUID) error
-
 }
@@ -6,11 +6,15 @@ import (
 	"coffee-tracker-backend/internal/infrastructure/config"
 	"coffee-tracker-backend/internal/infrastructure/database"
 	"coffee-tracker-backend/internal/infrastructure/http/handlers"
+	"coffee-tracker-backend/internal/infrastructure/http/middleware"
+	"coffee-tracker-backend/internal/infrastructure/importers"
 	"coffee-tracker-backend/internal/infrastructure/notifications"
+	"coffee-tracker-backend/internal/infrastructure/ratelimit"
 	"coffee-tracker-backend/internal/infrastructure/repositories"
 	"coffee-tracker-backend/internal/infrastructure/storage"
 	"coffee-tracker-backend/internal/usecases"
 	"fmt"
+	"time"
 )
 
 // initializeDependencies sets up all dependencies (database, repositories, use cases, handlers)
@@ -27,6 +31,8 @@ func (s *Server) initializeDependencies() error {
 	settingsRepo := repositories.NewUserSettingsRepositoryImpl(db)
 	authRepo := repositories.NewAuthRepositoryImpl(db)
 	genericKvRepo := repositories.NewGenericKVRepositoryImpl(db)
+	goalRepo := repositories.NewGoalRepositoryImpl(db)
+	exportRepo := repositories.NewDataExportRepositoryImpl(db)
 
 	// Initialize Supabase Storage client
 	if s.config.StorageURL == "" || s.config.ServiceRoleKey == "" {
@@ -46,17 +52,27 @@ func (s *Server) initializeDependencies() error {
 		)
 	 }
 
+	s.tokenService = auth.NewJWTService(s.config.JWTSecret, s.config.AccessTokenTTL, s.config.RefreshTokenTTL)
+	s.userCache = middleware.NewUserCache(userRepo, 5*time.Minute)
+
 	// Initialize use cases
-	createCoffeeUC := usecases.NewCreateCoffeeEntryUseCase(coffeeRepo)
-	updateCoffeeEntryUC := usecases.NewUpdateCoffeeEntryUseCase(coffeeRepo)
+	createCoffeeUC := usecases.NewCreateCoffeeEntryUseCase(coffeeRepo, genericKvRepo, settingsRepo)
+	updateCoffeeEntryUC := usecases.NewUpdateCoffeeEntryUseCase(coffeeRepo, genericKvRepo)
 	deleteCoffeeUC := usecases.NewDeleteCoffeeEntryUseCase(coffeeRepo)
-	clearCoffeeEntriesUC := usecases.NewClearCoffeeEntriesUseCase(coffeeRepo)
+	consumedTokenRepo := repositories.NewConsumedTokenRepositoryImpl(db)
+	clearCoffeeEntriesUC := usecases.NewClearCoffeeEntriesUseCase(coffeeRepo, s.tokenService, consumedTokenRepo)
 	getCoffeeEntriesUC := usecases.NewGetCoffeeEntriesUseCase(coffeeRepo)
 	getStatsUseCase := usecases.NewGetCoffeeStatsUseCase(coffeeRepo)
+	getDailyCaffeineUC := usecases.NewGetDailyCaffeineUseCase(coffeeRepo)
+	getCaffeineCurveUC := usecases.NewGetCaffeineCurveUseCase(coffeeRepo, settingsRepo)
+	getSeriesUC := usecases.NewGetCoffeeSeriesUseCase(coffeeRepo)
+	getHeatmapUC := usecases.NewGetConsumptionHeatmapUseCase(coffeeRepo)
+	getSpendingUC := usecases.NewGetSpendingReportUseCase(coffeeRepo)
 	getUserByIDUC := usecases.NewGetUserByIDUseCase(userRepo)
 	getUserByMobileUC := usecases.NewGetUserByMobileUseCase(userRepo)
-	generateOtpUC := usecases.NewGenerateOtpUseCase(authRepo, smsService, config.OtpStrength(s.config.OtpStrength))
-	validateOtpUC := usecases.NewValidateOtpUseCase(authRepo, s.config.MagicOtp)
+	otpAttemptRepo := repositories.NewOtpAttemptRepositoryImpl(db)
+	generateOtpUC := usecases.NewGenerateOtpUseCase(authRepo, otpAttemptRepo, smsService, config.OtpStrength(s.config.OtpStrength))
+	validateOtpUC := usecases.NewValidateOtpUseCase(authRepo, otpAttemptRepo, s.config.MagicOtp)
 	saveRefreshTokenUC := usecases.NewSaveRefreshTokenUseCase(authRepo)
 	getRefreshTokenUC := usecases.NewGetRefreshTokenUseCase(authRepo)
 	deleteRefreshTokenUC := usecases.NewDeleteRefreshTokenUseCase(authRepo)
@@ -76,13 +92,50 @@ func (s *Server) initializeDependencies() error {
 		deleteCoffeeUC,
 		clearCoffeeEntriesUC,
 		getStatsUseCase,
+		usecases.NewSearchCoffeeEntriesUseCase(coffeeRepo),
+		usecases.NewBatchCoffeeEntriesUseCase(coffeeRepo, genericKvRepo),
+	)
+	s.statsHandler = handlers.NewStatsHandler(
+		getDailyCaffeineUC,
+		getCaffeineCurveUC,
+		getSeriesUC,
+		getHeatmapUC,
+		getSpendingUC,
+	)
+	s.trashHandler = handlers.NewTrashHandler(
+		usecases.NewGetTrashUseCase(coffeeRepo),
+		usecases.NewRestoreCoffeeEntryUseCase(coffeeRepo),
+	)
+	s.exportHandler = handlers.NewExportHandler(
+		usecases.NewExportCoffeeEntriesUseCase(coffeeRepo, genericKvRepo),
+	)
+	importUC := usecases.NewImportCoffeeEntriesUseCase(coffeeRepo, genericKvRepo)
+	importUC.RegisterImporter("apple_health", importers.NewAppleHealthImporter(usecases.MaxImportRows))
+	importUC.RegisterImporter("ical", importers.NewICalendarImporter(usecases.MaxImportRows))
+	s.importHandler = handlers.NewImportHandler(importUC)
+	s.purgeDeletedUC = usecases.NewPurgeDeletedEntriesUseCase(coffeeRepo, s.config.TrashRetention)
+	s.purgeAccountsUC = usecases.NewPurgeDeletedAccountsUseCase(
+		userRepo,
+		exportRepo,
+		storageService,
+		s.config.ProfileImageBucket,
+		s.config.DataExportBucket,
+	)
+	s.syncHandler = handlers.NewSyncHandler(
+		usecases.NewGetSyncChangesUseCase(coffeeRepo),
+		usecases.NewApplySyncMutationsUseCase(coffeeRepo, genericKvRepo),
+	)
+	s.goalHandler = handlers.NewGoalHandler(
+		usecases.NewGetGoalsUseCase(goalRepo),
+		usecases.NewSetGoalUseCase(goalRepo),
+		usecases.NewDeleteGoalUseCase(goalRepo),
+		usecases.NewGetGoalProgressUseCase(goalRepo, coffeeRepo),
 	)
 	s.userSettingsHandler = handlers.NewUserSettingsHandler(
 		usecases.NewGetUserSettingsUseCase(settingsRepo),
 		usecases.NewUpdateUserSettingUseCase(settingsRepo),
 	)
 	s.healthHandler = handlers.NewHealthHandler()
-	s.tokenService = auth.NewJWTService(s.config.JWTSecret, s.config.AccessTokenTTL, s.config.RefreshTokenTTL)
 	s.authHandler = handlers.NewAuthHandler(
 		s.tokenService,
 		getUserByIDUC,
@@ -92,8 +145,16 @@ func (s *Server) initializeDependencies() error {
 		saveRefreshTokenUC,
 		getRefreshTokenUC,
 		deleteRefreshTokenUC,
+		usecases.NewCancelAccountDeletionUseCase(userRepo, s.userCache),
+		usecases.NewRegisterUserUseCase(userRepo, generateOtpUC),
+		usecases.NewActivateUserUseCase(userRepo),
 	)
 	s.userRepo = userRepo
+	s.idempotencyRepo = repositories.NewIdempotencyRepositoryImpl(db)
+	s.otpAttemptRepo = otpAttemptRepo
+	s.consumedTokenRepo = consumedTokenRepo
+	// In-memory buckets count per instance; plug in a shared ratelimit.Store when scaling out
+	s.rateLimitStore = ratelimit.NewMemoryStore()
 
 	s.genericKvHandler = handlers.NewGenericKVHandler(getGenericKvUC)
 
@@ -102,6 +163,20 @@ func (s *Server) initializeDependencies() error {
 		updateProfileUC,
 		uploadImageUC, 
 		deleteImageUC,
+		usecases.NewExportUserDataUseCase(
+			s.jobsCtx,
+			userRepo,
+			settingsRepo,
+			coffeeRepo,
+			goalRepo,
+			authRepo,
+			exportRepo,
+			storageService,
+			s.config.DataExportBucket,
+			s.config.ProfileImageBucket,
+			s.config.DataExportLinkTTL,
+		),
+		usecases.NewDeleteAccountUseCase(userRepo, authRepo, s.userCache, s.config.AccountDeletionGrace),
 	)
 
 	return nil
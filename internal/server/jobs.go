@@ -0,0 +1,66 @@
+// file: internal/server/jobs.go
+package server
+
+import (
+	"context"
+	"time"
+
+	"coffee-tracker-backend/internal/infrastructure/utils"
+	"coffee-tracker-backend/internal/usecases"
+)
+
+// startBackgroundJobs runs the periodic maintenance tasks until ctx is cancelled
+func (s *Server) startBackgroundJobs(ctx context.Context) {
+	go s.runPeriodically(ctx, s.config.PurgeInterval, s.purgeExpiredData)
+}
+
+// runPeriodically calls job once right away, then every interval
+func (s *Server) runPeriodically(ctx context.Context, interval time.Duration, job func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		job(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeExpiredData removes the entries past the trash retention, the accounts past their deletion
+// grace period, the expired Idempotency-Key records, the stale OTP attempt counters and the
+// expired consumed tokens
+func (s *Server) purgeExpiredData(ctx context.Context) {
+	if purged, err := s.purgeDeletedUC.Execute(ctx); err != nil {
+		s.Logger.Printf("⚠️ Purging deleted entries failed: %v", err)
+	} else if purged > 0 {
+		s.Logger.Printf("🗑️ Purged %d deleted entries", purged)
+	}
+
+	if purged, err := s.purgeAccountsUC.Execute(ctx); err != nil {
+		s.Logger.Printf("⚠️ Purging deleted accounts failed: %v", err)
+	} else if purged > 0 {
+		s.Logger.Printf("🗑️ Purged %d deleted accounts", purged)
+	}
+
+	if expired, err := s.idempotencyRepo.DeleteExpired(ctx, utils.NowUTC()); err != nil {
+		s.Logger.Printf("⚠️ Purging idempotency keys failed: %v", err)
+	} else if expired > 0 {
+		s.Logger.Printf("🗑️ Purged %d expired idempotency keys", expired)
+	}
+
+	if stale, err := s.otpAttemptRepo.DeleteExpired(ctx, utils.NowUTC().Add(-usecases.OtpFailureWindow)); err != nil {
+		s.Logger.Printf("⚠️ Purging OTP attempts failed: %v", err)
+	} else if stale > 0 {
+		s.Logger.Printf("🗑️ Purged %d stale OTP attempt counters", stale)
+	}
+
+	if expired, err := s.consumedTokenRepo.DeleteExpired(ctx, utils.NowUTC()); err != nil {
+		s.Logger.Printf("⚠️ Purging consumed tokens failed: %v", err)
+	} else if expired > 0 {
+		s.Logger.Printf("🗑️ Purged %d expired consumed tokens", expired)
+	}
+}
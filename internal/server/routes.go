@@ -3,7 +3,6 @@ package server
 
 import (
 	"net/http"
-	"time"
 
 	"coffee-tracker-backend/internal/infrastructure/http/middleware"
 
@@ -19,10 +18,13 @@ const (
 	settingsPrefix  = apiPrefix + "/settings"
 	genericKVPrefix = apiPrefix + "/kv"
 	statsPrefix     = apiPrefix + "/stats"
+	goalsPrefix     = apiPrefix + "/goals"
+	syncPrefix      = apiPrefix + "/sync"
 )
 
 // setupRoutes configures all routes and their middleware
 func (s *Server) setupRoutes() {
+	s.router.Use(middleware.ClientIP(s.config.TrustedProxies, s.config.ClientIPHeader))
 	s.router.Use(middleware.CorsMiddleware)
 	s.router.Use(middleware.RequestLogger) 
 	s.registerHealthRoutes()
@@ -50,8 +52,13 @@ func (s *Server) registerHealthRoutes() {
 func (s *Server) registerPublicRoutes() {
 	api := s.router.NewRoute().Subrouter()
 
-	api.HandleFunc(authPrefix+"/request-otp", s.authHandler.RequestOTP).Methods(http.MethodPost)
-	api.HandleFunc(authPrefix+"/verify-otp", s.authHandler.VerifyOTP).Methods(http.MethodPost)
+	// Every OTP route is limited per client IP; the routes sending an SMS also per mobile number
+	perIP := middleware.RateLimit(s.rateLimitStore, "auth-ip", s.config.RateLimitAuthIP, middleware.ByIP)
+	perMobile := middleware.RateLimit(s.rateLimitStore, "otp-mobile", s.config.RateLimitOtpMobile, middleware.ByMobile)
+
+	api.Handle(authPrefix+"/register", perIP(perMobile(http.HandlerFunc(s.authHandler.Register)))).Methods(http.MethodPost)
+	api.Handle(authPrefix+"/request-otp", perIP(perMobile(http.HandlerFunc(s.authHandler.RequestOTP)))).Methods(http.MethodPost)
+	api.Handle(authPrefix+"/verify-otp", perIP(http.HandlerFunc(s.authHandler.VerifyOTP))).Methods(http.MethodPost)
 	// /auth/refresh is public: it validates the refresh token itself, no access token needed
 	api.HandleFunc(authPrefix+"/refresh", s.authHandler.RefreshToken).Methods(http.MethodPost)
 }
@@ -62,16 +69,22 @@ func (s *Server) registerPublicRoutes() {
 func (s *Server) registerProtectedRoutes() {
 	api := s.router.NewRoute().Subrouter()
 	api.Use(middleware.AuthMiddleware(s.tokenService))
-	api.Use(middleware.UserMiddleware(s.userRepo, 5*time.Minute))
+	// Off unless RATE_LIMIT_USER is set
+	api.Use(middleware.RateLimit(s.rateLimitStore, "user", s.config.RateLimitUser, middleware.ByUser))
+	api.Use(middleware.UserMiddleware(s.userCache))
+	api.Use(middleware.Idempotency(s.idempotencyRepo, s.config.IdempotencyTTL))
 
 	// --- Auth routes ---
 	api.HandleFunc(authPrefix+"/logout", s.authHandler.Logout).Methods(http.MethodPost)
 
 	// --- User routes ---
+	api.HandleFunc(userPrefix, s.userHandler.DeleteAccount).Methods(http.MethodDelete)
 	api.HandleFunc(userPrefix+"/profile", s.userHandler.GetProfile).Methods(http.MethodGet)
 	api.HandleFunc(userPrefix+"/profile", s.userHandler.UpdateProfile).Methods(http.MethodPatch)
 	api.HandleFunc(userPrefix+"/avatar", s.userHandler.UploadProfileImage).Methods(http.MethodPost)
 	api.HandleFunc(userPrefix+"/avatar", s.userHandler.DeleteProfileImage).Methods(http.MethodDelete)
+	api.HandleFunc(userPrefix+"/export", s.userHandler.StartDataExport).Methods(http.MethodPost)
+	api.HandleFunc(userPrefix+"/export/{id}", s.userHandler.GetDataExport).Methods(http.MethodGet)
 
 	// --- Generic KV store ---
 	api.HandleFunc(genericKVPrefix, s.genericKvHandler.Get).Methods(http.MethodGet)
@@ -79,11 +92,34 @@ func (s *Server) registerProtectedRoutes() {
 	// --- Coffee entries ---
 	api.HandleFunc(entriesPrefix, s.coffeeHandler.GetAll).Methods(http.MethodGet)
 	api.HandleFunc(entriesPrefix, s.coffeeHandler.Create).Methods(http.MethodPost)
+	api.HandleFunc(entriesPrefix, s.coffeeHandler.ClearAll).Methods(http.MethodDelete)
+	api.HandleFunc(entriesPrefix+"/clear-confirmation", s.coffeeHandler.ClearConfirmation).Methods(http.MethodPost)
+	api.HandleFunc(entriesPrefix+":batch", s.coffeeHandler.Batch).Methods(http.MethodPost)
+	api.HandleFunc(entriesPrefix+"/search", s.coffeeHandler.Search).Methods(http.MethodGet)
+	api.HandleFunc(entriesPrefix+"/export", s.exportHandler.Export).Methods(http.MethodGet)
+	api.HandleFunc(entriesPrefix+"/import", s.importHandler.Import).Methods(http.MethodPost)
+	api.HandleFunc(entriesPrefix+"/trash", s.trashHandler.GetAll).Methods(http.MethodGet)
+	api.HandleFunc(entriesPrefix+"/{id}/restore", s.trashHandler.Restore).Methods(http.MethodPost)
 	api.HandleFunc(entriesPrefix+"/{id}", s.coffeeHandler.Update).Methods(http.MethodPut)
 	api.HandleFunc(entriesPrefix+"/{id}", s.coffeeHandler.Delete).Methods(http.MethodDelete)
 
+	// --- Offline sync ---
+	api.HandleFunc(syncPrefix, s.syncHandler.GetChanges).Methods(http.MethodGet)
+	api.HandleFunc(syncPrefix, s.syncHandler.Apply).Methods(http.MethodPost)
+
 	// --- Stats ---
 	api.HandleFunc(statsPrefix, s.coffeeHandler.GetStats).Methods(http.MethodGet)
+	api.HandleFunc(statsPrefix+"/caffeine", s.statsHandler.GetDailyCaffeine).Methods(http.MethodGet)
+	api.HandleFunc(statsPrefix+"/caffeine-curve", s.statsHandler.GetCaffeineCurve).Methods(http.MethodGet)
+	api.HandleFunc(statsPrefix+"/series", s.statsHandler.GetSeries).Methods(http.MethodGet)
+	api.HandleFunc(statsPrefix+"/heatmap", s.statsHandler.GetHeatmap).Methods(http.MethodGet)
+	api.HandleFunc(statsPrefix+"/spending", s.statsHandler.GetSpending).Methods(http.MethodGet)
+
+	// --- Goals ---
+	api.HandleFunc(goalsPrefix, s.goalHandler.GetAll).Methods(http.MethodGet)
+	api.HandleFunc(goalsPrefix, s.goalHandler.Set).Methods(http.MethodPut)
+	api.HandleFunc(goalsPrefix+"/progress", s.goalHandler.GetProgress).Methods(http.MethodGet)
+	api.HandleFunc(goalsPrefix+"/{id}", s.goalHandler.Delete).Methods(http.MethodDelete)
 
 	// --- User settings ---
 	api.HandleFunc(settingsPrefix, s.userSettingsHandler.GetAll).Methods(http.MethodGet)
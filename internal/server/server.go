@@ -11,7 +11,10 @@ import (
 	"coffee-tracker-backend/internal/infrastructure/auth"
 	"coffee-tracker-backend/internal/infrastructure/config"
 	"coffee-tracker-backend/internal/infrastructure/http/handlers"
+	"coffee-tracker-backend/internal/infrastructure/http/middleware"
+	"coffee-tracker-backend/internal/infrastructure/ratelimit"
 	"coffee-tracker-backend/internal/repositories"
+	"coffee-tracker-backend/internal/usecases"
 
 	"github.com/gorilla/mux"
 )
@@ -25,11 +28,26 @@ type Server struct {
 	userHandler         *handlers.UserHandler
 	genericKvHandler    *handlers.GenericKVHandler
 	coffeeHandler       *handlers.CoffeeEntryHandler
+	statsHandler        *handlers.StatsHandler
+	goalHandler         *handlers.GoalHandler
+	syncHandler         *handlers.SyncHandler
 	userSettingsHandler *handlers.UserSettingsHandler
 	healthHandler       *handlers.HealthHandler
 	authHandler         *handlers.AuthHandler
 	tokenService        auth.TokenService
 	userRepo            repositories.UserRepository
+	userCache           *middleware.UserCache
+	idempotencyRepo     repositories.IdempotencyRepository
+	trashHandler        *handlers.TrashHandler
+	exportHandler       *handlers.ExportHandler
+	importHandler       *handlers.ImportHandler
+	purgeDeletedUC      *usecases.PurgeDeletedEntriesUseCase
+	purgeAccountsUC     *usecases.PurgeDeletedAccountsUseCase
+	otpAttemptRepo      repositories.OtpAttemptRepository
+	consumedTokenRepo   repositories.ConsumedTokenRepository
+	rateLimitStore      ratelimit.Store
+	jobsCtx             context.Context // cancelled at shutdown, bounds all background work
+	stopJobs            context.CancelFunc
 }
 
 // NewServer initializes a new Server instance with all dependencies
@@ -48,6 +66,7 @@ func NewServer() (*Server, error) {
 		router: mux.NewRouter(),
 		Logger: logger,
 	}
+	server.jobsCtx, server.stopJobs = context.WithCancel(context.Background())
 
 	// Initialize dependencies and routes
 	if err := server.initializeDependencies(); err != nil {
@@ -72,12 +91,18 @@ func NewServer() (*Server, error) {
 func (s *Server) Start() error {
 	s.logServerInfo()
 	s.Logger.Printf("🚀 Starting server on port %s", s.config.Port)
+
+	s.startBackgroundJobs(s.jobsCtx)
+
 	return s.httpServer.ListenAndServe()
 }
 
 // Shutdown gracefully stops the server
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.Logger.Println("🧹 Shutting down server...")
+	if s.stopJobs != nil {
+		s.stopJobs()
+	}
 	// Attempt graceful shutdown
 	return s.httpServer.Shutdown(ctx)
 }
@@ -0,0 +1,211 @@
+// file: internal/usecases/apply_sync_mutations.go
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/infrastructure/http/models"
+	"coffee-tracker-backend/internal/infrastructure/utils"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxSyncMutations = 500
+	// maxSyncClockSkew bounds how far in the future a client change may be dated,
+	// otherwise a device with a wrong clock would win every conflict
+	maxSyncClockSkew = 5 * time.Minute
+)
+
+type ApplySyncMutationsUseCase struct {
+	coffeeRepo repositories.CoffeeEntryRepository
+	kvRepo     repositories.GenericKVRepository
+}
+
+func NewApplySyncMutationsUseCase(coffeeRepo repositories.CoffeeEntryRepository, kvRepo repositories.GenericKVRepository) *ApplySyncMutationsUseCase {
+	return &ApplySyncMutationsUseCase{
+		coffeeRepo: coffeeRepo,
+		kvRepo:     kvRepo,
+	}
+}
+
+// Execute applies the client mutations in order, in a single transaction. Conflicts are resolved
+// per entry with last-write-wins on updated_at (a delete counts as a write at its updated_at).
+// Invalid mutations are rejected individually; only a storage failure fails the whole batch.
+func (uc *ApplySyncMutationsUseCase) Execute(ctx context.Context, userID uuid.UUID, req *models.SyncRequest) ([]entities.SyncMutationResult, error) {
+	if len(req.Mutations) == 0 || len(req.Mutations) > maxSyncMutations {
+		return nil, ErrInvalidInput
+	}
+
+	var results []entities.SyncMutationResult
+	err := uc.coffeeRepo.WithinTx(ctx, func(tx repositories.CoffeeEntryRepository) error {
+		// Two devices syncing at once must not both insert the same entry
+		if err := tx.LockUser(ctx, userID); err != nil {
+			return err
+		}
+
+		results = make([]entities.SyncMutationResult, 0, len(req.Mutations))
+		for i := range req.Mutations {
+			result, err := uc.apply(ctx, tx, userID, &req.Mutations[i])
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	return results, nil
+}
+
+// apply handles one mutation; returned errors are storage failures
+func (uc *ApplySyncMutationsUseCase) apply(ctx context.Context, tx repositories.CoffeeEntryRepository, userID uuid.UUID, m *models.SyncMutation) (entities.SyncMutationResult, error) {
+	result := entities.SyncMutationResult{ID: m.ID}
+	reject := func(reason string) (entities.SyncMutationResult, error) {
+		result.Status = entities.SyncRejected
+		result.Error = reason
+		return result, nil
+	}
+
+	if m.ID == uuid.Nil {
+		return reject("missing id")
+	}
+	if m.UpdatedAt.IsZero() || m.UpdatedAt.After(utils.NowUTC().Add(maxSyncClockSkew)) {
+		return reject("missing or future updated_at")
+	}
+
+	// The id may belong to another user's entry, even one in their trash or purged
+	owner, err := tx.GetOwner(ctx, m.ID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return result, err
+	}
+	if err == nil && owner != userID {
+		return reject("id already in use")
+	}
+
+	existing, err := tx.GetByID(ctx, m.ID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return result, err
+	}
+
+	switch entities.SyncOp(m.Op) {
+	case entities.SyncOpUpsert:
+		if m.Entry == nil {
+			return reject("missing entry")
+		}
+		entry, err := uc.buildEntry(ctx, userID, m)
+		if err != nil {
+			if err == ErrInvalidInput {
+				return reject("invalid entry")
+			}
+			return result, err
+		}
+
+		if existing != nil {
+			switch {
+			case m.UpdatedAt.Equal(existing.UpdatedAt):
+				result.Status, result.Entry = entities.SyncUnchanged, existing
+			case m.UpdatedAt.Before(existing.UpdatedAt):
+				result.Status, result.Entry = entities.SyncConflict, existing
+			default:
+				if err := tx.Replace(ctx, entry); err != nil {
+					return result, err
+				}
+				result.Status, result.Entry = entities.SyncApplied, entry
+			}
+			return result, nil
+		}
+
+		tombstone, err := tx.GetTombstone(ctx, m.ID, userID)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return result, err
+		}
+		if tombstone != nil {
+			if !m.UpdatedAt.After(tombstone.DeletedAt) {
+				result.Status, result.Deleted = entities.SyncConflict, true
+				return result, nil
+			}
+			// Edited after the delete: bring the entry back, from the trash if it is still there
+			_, err := tx.Restore(ctx, m.ID, userID)
+			if err == nil {
+				if err := tx.Replace(ctx, entry); err != nil {
+					return result, err
+				}
+				result.Status, result.Entry = entities.SyncApplied, entry
+				return result, nil
+			}
+			if !errors.Is(err, repositories.ErrNotFound) {
+				return result, err
+			}
+			if err := tx.DeleteTombstone(ctx, m.ID, userID); err != nil {
+				return result, err
+			}
+		}
+
+		if err := tx.Create(ctx, entry); err != nil {
+			return result, err
+		}
+		result.Status, result.Entry = entities.SyncApplied, entry
+		return result, nil
+
+	case entities.SyncOpDelete:
+		if existing == nil {
+			// Never synced or already deleted
+			result.Status = entities.SyncUnchanged
+			return result, nil
+		}
+		if m.UpdatedAt.Before(existing.UpdatedAt) {
+			result.Status, result.Entry = entities.SyncConflict, existing
+			return result, nil
+		}
+		if err := tx.DeleteAt(ctx, m.ID, userID, m.UpdatedAt); err != nil {
+			return result, err
+		}
+		result.Status = entities.SyncApplied
+		return result, nil
+	}
+
+	return reject("unknown op")
+}
+
+// buildEntry validates the client state of an upsert like CreateCoffeeEntryUseCase does
+func (uc *ApplySyncMutationsUseCase) buildEntry(ctx context.Context, userID uuid.UUID, m *models.SyncMutation) (*entities.CoffeeEntry, error) {
+	data := m.Entry
+	if data.Timestamp.IsZero() || !isValidRating(data.Rating) {
+		return nil, ErrInvalidInput
+	}
+
+	currency, err := validatePrice(data.Price, data.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	caffeine, err := resolveCaffeine(ctx, uc.kvRepo, data.Caffeine, data.CoffeeType, data.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.CoffeeEntry{
+		ID:           m.ID,
+		UserID:       userID,
+		CoffeeTypeID: data.CoffeeType,
+		SizeID:       data.Size,
+		Caffeine:     caffeine,
+		Notes:        data.Notes,
+		Price:        data.Price,
+		Currency:     currency,
+		Rating:       data.Rating,
+		Latitude:     data.Latitude,
+		Longitude:    data.Longitude,
+		Timestamp:    data.Timestamp,
+		CreatedAt:    m.UpdatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,136 @@
+// file: internal/usecases/batch_coffee_entries.go
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/infrastructure/http/models"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// MaxBatchOperations bounds the size of a single batch request
+const MaxBatchOperations = 100
+
+// errBatchAborted rolls the batch transaction back after an operation failed
+var errBatchAborted = errors.New("batch aborted")
+
+type BatchCoffeeEntriesUseCase struct {
+	coffeeRepo repositories.CoffeeEntryRepository
+	kvRepo     repositories.GenericKVRepository
+}
+
+func NewBatchCoffeeEntriesUseCase(coffeeRepo repositories.CoffeeEntryRepository, kvRepo repositories.GenericKVRepository) *BatchCoffeeEntriesUseCase {
+	return &BatchCoffeeEntriesUseCase{
+		coffeeRepo: coffeeRepo,
+		kvRepo:     kvRepo,
+	}
+}
+
+// Execute applies the operations in order in one transaction: either all of them succeed, or none
+// is applied. On ErrInvalidInput (a bad operation) or ErrNotFound (an unknown entry) the results
+// tell which operations failed; the others are reported as not applied.
+func (uc *BatchCoffeeEntriesUseCase) Execute(ctx context.Context, userID uuid.UUID, req *models.BatchRequest) ([]entities.BatchItemResult, error) {
+	if len(req.Operations) == 0 || len(req.Operations) > MaxBatchOperations {
+		return nil, ErrInvalidInput
+	}
+
+	// Validate everything before touching the database
+	results := make([]entities.BatchItemResult, len(req.Operations))
+	entries := make([]*entities.CoffeeEntry, len(req.Operations))
+	invalid := false
+	for i, op := range req.Operations {
+		results[i] = entities.BatchItemResult{Index: i, Op: entities.BatchOp(op.Op), ID: op.ID}
+
+		entry, err := uc.buildEntry(ctx, userID, &op)
+		switch {
+		case err == nil:
+			entries[i] = entry
+			if entry != nil {
+				results[i].ID = entry.ID
+			}
+		case err == ErrInvalidInput:
+			results[i].Status, results[i].Error = entities.BatchItemFailed, "invalid operation"
+			invalid = true
+		default:
+			return nil, err
+		}
+	}
+	if invalid {
+		markNotApplied(results)
+		return results, ErrInvalidInput
+	}
+
+	failedErr := ErrInternalError
+	err := uc.coffeeRepo.WithinTx(ctx, func(tx repositories.CoffeeEntryRepository) error {
+		for i := range results {
+			result := &results[i]
+
+			var err error
+			switch result.Op {
+			case entities.BatchCreate:
+				err = tx.Create(ctx, entries[i])
+				result.Status, result.Entry = entities.BatchItemCreated, entries[i]
+			case entities.BatchUpdate:
+				err = tx.Update(ctx, entries[i])
+				result.Status, result.Entry = entities.BatchItemUpdated, entries[i]
+			case entities.BatchDelete:
+				err = tx.Delete(ctx, result.ID, userID)
+				result.Status = entities.BatchItemDeleted
+			}
+
+			if errors.Is(err, repositories.ErrNotFound) {
+				result.Status, result.Entry, result.Error = entities.BatchItemFailed, nil, "entry not found"
+				failedErr = ErrNotFound
+				return errBatchAborted
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if err == errBatchAborted {
+			markNotApplied(results)
+			return results, failedErr
+		}
+		return nil, ErrInternalError
+	}
+
+	return results, nil
+}
+
+// buildEntry validates one operation and returns the entry to write (nil for deletes)
+func (uc *BatchCoffeeEntriesUseCase) buildEntry(ctx context.Context, userID uuid.UUID, op *models.BatchOperation) (*entities.CoffeeEntry, error) {
+	switch entities.BatchOp(op.Op) {
+	case entities.BatchCreate:
+		if op.Create == nil {
+			return nil, ErrInvalidInput
+		}
+		return newCoffeeEntry(ctx, uc.kvRepo, userID, op.Create)
+	case entities.BatchUpdate:
+		if op.Update == nil || op.ID == uuid.Nil {
+			return nil, ErrInvalidInput
+		}
+		return updatedCoffeeEntry(ctx, uc.kvRepo, userID, op.ID, op.Update)
+	case entities.BatchDelete:
+		if op.ID == uuid.Nil {
+			return nil, ErrInvalidInput
+		}
+		return nil, nil
+	}
+	return nil, ErrInvalidInput
+}
+
+// markNotApplied flags every operation that did not fail itself as rolled back
+func markNotApplied(results []entities.BatchItemResult) {
+	for i := range results {
+		if results[i].Status != entities.BatchItemFailed {
+			results[i].Status, results[i].Entry = entities.BatchItemNotApplied, nil
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// file: internal/usecases/caffeine.go
+package usecases
+
+import (
+	"context"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/repositories"
+)
+
+// resolveCaffeine returns the caffeine (mg) to store on an entry: the client override when present,
+// otherwise an estimate based on the coffee type and size lookups.
+func resolveCaffeine(ctx context.Context, kvRepo repositories.GenericKVRepository, override, coffeeTypeID, sizeID *int) (*int, error) {
+	if override != nil {
+		if *override < 0 || *override > entities.MaxCaffeinePerEntryMg {
+			return nil, ErrInvalidInput
+		}
+		return override, nil
+	}
+
+	model, err := kvRepo.GetCaffeineModel(ctx)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	return model.Estimate(coffeeTypeID, sizeID), nil
+}
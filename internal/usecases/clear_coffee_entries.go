@@ -2,31 +2,99 @@
 package usecases
 
 import (
+	"coffee-tracker-backend/internal/infrastructure/http/models"
 	"coffee-tracker-backend/internal/repositories"
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// clearConfirmationTTL is how long a clear confirmation token can be used
+const clearConfirmationTTL = 5 * time.Minute
+
+// ConfirmationTokenService issues and checks short-lived tokens confirming a destructive operation.
+// The scope binds a token to one operation, so it can't be reused for another one.
+// ValidateConfirmationToken returns the token's jti and expiry.
+type ConfirmationTokenService interface {
+	GenerateConfirmationToken(userID uuid.UUID, scope string, ttl time.Duration) (string, time.Time, error)
+	ValidateConfirmationToken(tokenString string, userID uuid.UUID, scope string) (string, time.Time, error)
+}
+
 type ClearCoffeeEntriesUseCase struct {
-	coffeeRepo repositories.CoffeeEntryRepository
+	coffeeRepo   repositories.CoffeeEntryRepository
+	confirmation ConfirmationTokenService
+	consumedRepo repositories.ConsumedTokenRepository
 }
 
-func NewClearCoffeeEntriesUseCase(coffeeRepo repositories.CoffeeEntryRepository) *ClearCoffeeEntriesUseCase {
+func NewClearCoffeeEntriesUseCase(coffeeRepo repositories.CoffeeEntryRepository, confirmation ConfirmationTokenService, consumedRepo repositories.ConsumedTokenRepository) *ClearCoffeeEntriesUseCase {
 	return &ClearCoffeeEntriesUseCase{
-		coffeeRepo: coffeeRepo,
+		coffeeRepo:   coffeeRepo,
+		confirmation: confirmation,
+		consumedRepo: consumedRepo,
 	}
 }
 
-// Execute deletes a coffee entry for a given user
-func (uc *ClearCoffeeEntriesUseCase) Execute(ctx context.Context, userID uuid.UUID) error {
-	err := uc.coffeeRepo.DeleteAll(ctx, userID)
+// RequestConfirmation issues the token needed to clear the given range, along with the
+// number of entries it currently holds so the client can show what is about to be deleted.
+func (uc *ClearCoffeeEntriesUseCase) RequestConfirmation(ctx context.Context, userID uuid.UUID, q *models.ClearCoffeeEntriesQuery) (string, time.Time, int, error) {
+	from, to, err := daysRangeUTC(q.From, q.To, q.TzOffset)
 	if err != nil {
-		if err.Error() == "no coffee entries for userid "+userID.String() {
-			return ErrNotFound
-		}
-		return ErrInternalError
+		return "", time.Time{}, 0, err
+	}
+
+	count, err := uc.coffeeRepo.GetCountInRange(ctx, userID, from, to)
+	if err != nil {
+		return "", time.Time{}, 0, ErrInternalError
+	}
+
+	token, expiresAt, err := uc.confirmation.GenerateConfirmationToken(userID, clearScope(from, to), clearConfirmationTTL)
+	if err != nil {
+		return "", time.Time{}, 0, ErrInternalError
+	}
+
+	return token, expiresAt, count, nil
+}
+
+// Execute moves the user's entries in the range to the trash and returns how many were moved.
+// The confirmation token must have been issued for the same user and the same range, and works once.
+func (uc *ClearCoffeeEntriesUseCase) Execute(ctx context.Context, userID uuid.UUID, q *models.ClearCoffeeEntriesQuery) (int64, error) {
+	from, to, err := daysRangeUTC(q.From, q.To, q.TzOffset)
+	if err != nil {
+		return 0, err
 	}
 
-	return nil
+	if q.ConfirmationToken == "" {
+		return 0, ErrConfirmationRequired
+	}
+	jti, expiresAt, err := uc.confirmation.ValidateConfirmationToken(q.ConfirmationToken, userID, clearScope(from, to))
+	if err != nil {
+		return 0, ErrInvalidConfirmation
+	}
+	consumed, err := uc.consumedRepo.Consume(ctx, jti, expiresAt)
+	if err != nil {
+		return 0, ErrInternalError
+	}
+	if !consumed {
+		return 0, ErrInvalidConfirmation
+	}
+
+	deleted, err := uc.coffeeRepo.DeleteAll(ctx, userID, from, to)
+	if err != nil {
+		return 0, ErrInternalError
+	}
+
+	return deleted, nil
+}
+
+// clearScope names the resolved range, so a token for one period can't clear another (or everything)
+func clearScope(from, to *time.Time) string {
+	bound := func(t *time.Time) string {
+		if t == nil {
+			return "*"
+		}
+		return fmt.Sprint(t.Unix())
+	}
+	return "clear_entries:" + bound(from) + ":" + bound(to)
 }
@@ -3,6 +3,7 @@ package usecases
 
 import (
 	"context"
+	"time"
 
 	"coffee-tracker-backend/internal/entities"
 	"coffee-tracker-backend/internal/infrastructure/http/models"
@@ -13,40 +14,109 @@ import (
 )
 
 type CreateCoffeeEntryUseCase struct {
-	coffeeRepo repositories.CoffeeEntryRepository
+	coffeeRepo   repositories.CoffeeEntryRepository
+	kvRepo       repositories.GenericKVRepository
+	settingsRepo repositories.UserSettingsRepository
 }
 
-func NewCreateCoffeeEntryUseCase(coffeeRepo repositories.CoffeeEntryRepository) *CreateCoffeeEntryUseCase {
+func NewCreateCoffeeEntryUseCase(coffeeRepo repositories.CoffeeEntryRepository, kvRepo repositories.GenericKVRepository, settingsRepo repositories.UserSettingsRepository) *CreateCoffeeEntryUseCase {
 	return &CreateCoffeeEntryUseCase{
-		coffeeRepo: coffeeRepo,
+		coffeeRepo:   coffeeRepo,
+		kvRepo:       kvRepo,
+		settingsRepo: settingsRepo,
 	}
 }
 
-func (uc *CreateCoffeeEntryUseCase) Execute(ctx context.Context, userID uuid.UUID, req *models.CreateCoffeeEntryRequest) (*entities.CoffeeEntry, error) {
+// Execute inserts the entry and, when the user has daily limits configured, reports how the
+// entry's local day compares to them. The limit status is nil when no limit is set.
+func (uc *CreateCoffeeEntryUseCase) Execute(ctx context.Context, userID uuid.UUID, req *models.CreateCoffeeEntryRequest) (*entities.CoffeeEntry, *entities.DailyLimitStatus, error) {
 
-	// if req.Rating < 1 || req.Rating > 5 {
-	// 	return nil, ErrInvalidInput
-	// }
+	entry, err := newCoffeeEntry(ctx, uc.kvRepo, userID, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := uc.coffeeRepo.Create(ctx, entry); err != nil {
+		return nil, nil, ErrInternalError
+	}
+
+	// The entry is already stored, so a failed limit check must not fail the request
+	loc := entry.Timestamp.Location()
+	if req.TzOffset != nil {
+		loc = userLocation(req.TzOffset)
+	}
+	limitStatus, _ := uc.dailyLimitStatus(ctx, userID, entry, loc)
+
+	return entry, limitStatus, nil
+}
+
+// newCoffeeEntry validates a create request and builds the entry to insert
+func newCoffeeEntry(ctx context.Context, kvRepo repositories.GenericKVRepository, userID uuid.UUID, req *models.CreateCoffeeEntryRequest) (*entities.CoffeeEntry, error) {
+	if !isValidRating(req.Rating) {
+		return nil, ErrInvalidInput
+	}
+
+	caffeine, err := resolveCaffeine(ctx, kvRepo, req.Caffeine, req.CoffeeType, req.Size)
+	if err != nil {
+		return nil, err
+	}
 
-	entry := &entities.CoffeeEntry{
+	currency, err := validatePrice(req.Price, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.CoffeeEntry{
 		ID:         uuid.New(),
 		UserID:     userID,
 		CoffeeTypeID: req.CoffeeType,
 		SizeID:       req.Size,
-		// Caffeine:   req.Caffeine,
+		Caffeine:   caffeine,
 		Notes:      req.Notes,
-		// Price:      req.Price,
-		// Rating:     req.Rating,
+		Price:      req.Price,
+		Currency:   currency,
+		Rating:     req.Rating,
 		Latitude:   req.Latitude,
     	Longitude:  req.Longitude,
 		Timestamp: req.Timestamp,
 		CreatedAt:  utils.NowUTC(),
 		UpdatedAt:  utils.NowUTC(),
+	}, nil
+}
+
+// dailyLimitStatus computes the day totals (including the new entry) against the user's limits
+func (uc *CreateCoffeeEntryUseCase) dailyLimitStatus(ctx context.Context, userID uuid.UUID, entry *entities.CoffeeEntry, loc *time.Location) (*entities.DailyLimitStatus, error) {
+	settings, err := uc.settingsRepo.Get(ctx, userID)
+	if err != nil || !settings.HasLimits() {
+		return nil, err
 	}
 
-	if err := uc.coffeeRepo.Create(ctx, entry); err != nil {
-		return nil, ErrInternalError
+	dayStart, dayEnd := localDayRangeUTC(entry.Timestamp, loc)
+	caffeineToday, cupsToday, err := uc.coffeeRepo.GetCaffeineTotal(ctx, userID, dayStart, dayEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	entryCaffeine := 0
+	if entry.Caffeine != nil {
+		entryCaffeine = *entry.Caffeine
+	}
+
+	status := &entities.DailyLimitStatus{
+		Date:            dayStart.In(loc).Format(dateLayout),
+		CupsToday:       cupsToday,
+		CaffeineTodayMg: caffeineToday,
+		CupLimit:        settings.DailyCupLimit,
+		CaffeineLimitMg: settings.DailyCaffeineLimitMg,
+	}
+	if limit := settings.DailyCupLimit; limit != nil {
+		status.OverCupLimit = cupsToday > *limit
+		status.CrossedCupLimit = status.OverCupLimit && cupsToday-1 <= *limit
+	}
+	if limit := settings.DailyCaffeineLimitMg; limit != nil {
+		status.OverCaffeineLimit = caffeineToday > *limit
+		status.CrossedCaffeineLimit = status.OverCaffeineLimit && caffeineToday-entryCaffeine <= *limit
 	}
 
-	return entry, nil
+	return status, nil
 }
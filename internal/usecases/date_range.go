@@ -0,0 +1,162 @@
+// file: internal/usecases/date_range.go
+package usecases
+
+import (
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/infrastructure/utils"
+)
+
+const dateLayout = "2006-01-02"
+
+// userLocation returns the fixed zone for a client timezone offset (minutes east of UTC),
+// or UTC when no offset was provided.
+func userLocation(tzOffsetMinutes *int) *time.Location {
+	if tzOffsetMinutes == nil {
+		return time.UTC
+	}
+	return time.FixedZone("UserOffset", *tzOffsetMinutes*60)
+}
+
+// dayRangeUTC returns the UTC bounds [start, end) of a "2006-01-02" calendar day in the user's timezone.
+// An empty dateStr means "today" in that timezone.
+func dayRangeUTC(dateStr string, tzOffsetMinutes *int) (time.Time, time.Time, error) {
+	var baseTime time.Time
+	if dateStr == "" {
+		baseTime = utils.NowUTC().In(userLocation(tzOffsetMinutes))
+	} else {
+		parsed, err := time.Parse(dateLayout, dateStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidInput
+		}
+		baseTime = parsed
+	}
+
+	var start time.Time
+	if tzOffsetMinutes != nil {
+		start = adjustTimeWithOffsetMinutes(baseTime, *tzOffsetMinutes)
+	} else {
+		start = time.Date(baseTime.Year(), baseTime.Month(), baseTime.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	utcStart := start.UTC()
+	return utcStart, utcStart.Add(24 * time.Hour), nil
+}
+
+// daysRangeUTC returns the UTC bounds [from, to) of the inclusive local days fromStr..toStr.
+// An empty day leaves that side open (nil); a range ending before it starts is ErrInvalidInput.
+func daysRangeUTC(fromStr, toStr string, tzOffsetMinutes *int) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if fromStr != "" {
+		start, _, err := dayRangeUTC(fromStr, tzOffsetMinutes)
+		if err != nil {
+			return nil, nil, err
+		}
+		from = &start
+	}
+	if toStr != "" {
+		_, end, err := dayRangeUTC(toStr, tzOffsetMinutes)
+		if err != nil {
+			return nil, nil, err
+		}
+		to = &end
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, ErrInvalidInput
+	}
+	return from, to, nil
+}
+
+// localDayRangeUTC returns the UTC bounds [start, end) of the calendar day containing t in loc.
+func localDayRangeUTC(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	start := startOfDay(t, loc)
+	return start.UTC(), start.AddDate(0, 0, 1).UTC()
+}
+
+// loadTimezone resolves an IANA timezone name, defaulting to UTC when empty.
+func loadTimezone(tz string) (*time.Location, error) {
+	if tz == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, ErrInvalidInput
+	}
+	return loc, nil
+}
+
+// parseLocalDate parses a "2006-01-02" date as midnight in loc
+func parseLocalDate(dateStr string, loc *time.Location) (time.Time, error) {
+	parsed, err := time.ParseInLocation(dateLayout, dateStr, loc)
+	if err != nil {
+		return time.Time{}, ErrInvalidInput
+	}
+	return parsed, nil
+}
+
+// startOfDay returns midnight of t's calendar day in loc
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
+
+// bucketStart aligns a local midnight to the start of its bucket (Monday for weeks, the 1st for months),
+// matching Postgres date_trunc.
+func bucketStart(day time.Time, bucket entities.StatsBucket) time.Time {
+	switch bucket {
+	case entities.BucketWeek:
+		daysSinceMonday := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -daysSinceMonday)
+	case entities.BucketMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	default:
+		return day
+	}
+}
+
+// nextBucket returns the start of the bucket following the one starting at start
+func nextBucket(start time.Time, bucket entities.StatsBucket) time.Time {
+	switch bucket {
+	case entities.BucketWeek:
+		return start.AddDate(0, 0, 7)
+	case entities.BucketMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// resolveDateRange parses optional inclusive "from"/"to" dates in loc. "to" defaults to today
+// and "from" to a range that fits the bucket size (30 days, 12 weeks or 12 months).
+func resolveDateRange(fromStr, toStr string, bucket entities.StatsBucket, loc *time.Location) (time.Time, time.Time, error) {
+	to := startOfDay(utils.NowUTC(), loc)
+	if toStr != "" {
+		parsed, err := parseLocalDate(toStr, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = parsed
+	}
+
+	var from time.Time
+	switch {
+	case fromStr != "":
+		parsed, err := parseLocalDate(fromStr, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = parsed
+	case bucket == entities.BucketWeek:
+		from = to.AddDate(0, 0, -7*11)
+	case bucket == entities.BucketMonth:
+		from = to.AddDate(0, -11, 0)
+	default:
+		from = to.AddDate(0, 0, -29)
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, ErrInvalidInput
+	}
+	return from, to, nil
+}
@@ -0,0 +1,70 @@
+// file: internal/usecases/delete_account.go
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"coffee-tracker-backend/internal/infrastructure/utils"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// UserCache is a cache of users (e.g. the one of the request middleware) to clear when a user's status changes
+type UserCache interface {
+	Evict(userID uuid.UUID)
+}
+
+type DeleteAccountUseCase struct {
+	userRepo    repositories.UserRepository
+	authRepo    repositories.AuthRepository
+	userCache   UserCache
+	gracePeriod time.Duration
+}
+
+func NewDeleteAccountUseCase(userRepo repositories.UserRepository, authRepo repositories.AuthRepository, userCache UserCache, gracePeriod time.Duration) *DeleteAccountUseCase {
+	return &DeleteAccountUseCase{
+		userRepo:    userRepo,
+		authRepo:    authRepo,
+		userCache:   userCache,
+		gracePeriod: gracePeriod,
+	}
+}
+
+// Execute deletes the account and signs it out of every device. Its data is kept until the returned
+// time, when PurgeDeletedAccountsUseCase removes it; logging in again before then cancels the deletion.
+func (uc *DeleteAccountUseCase) Execute(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	purgeAt := utils.NowUTC().Add(uc.gracePeriod)
+	if err := uc.userRepo.ScheduleDeletion(ctx, userID, purgeAt); err != nil {
+		return time.Time{}, ErrInternalError
+	}
+	uc.userCache.Evict(userID)
+	if err := uc.authRepo.InvalidateAllUserTokens(ctx, userID); err != nil {
+		return time.Time{}, ErrInternalError
+	}
+	return purgeAt, nil
+}
+
+type CancelAccountDeletionUseCase struct {
+	userRepo  repositories.UserRepository
+	userCache UserCache
+}
+
+func NewCancelAccountDeletionUseCase(userRepo repositories.UserRepository, userCache UserCache) *CancelAccountDeletionUseCase {
+	return &CancelAccountDeletionUseCase{userRepo: userRepo, userCache: userCache}
+}
+
+// Execute restores a deleted account during its grace period.
+// Returns ErrUserNotFound once the grace period is over.
+func (uc *CancelAccountDeletionUseCase) Execute(ctx context.Context, userID uuid.UUID) error {
+	restored, err := uc.userRepo.CancelDeletion(ctx, userID)
+	if err != nil {
+		return ErrInternalError
+	}
+	if !restored {
+		return ErrUserNotFound
+	}
+	uc.userCache.Evict(userID)
+	return nil
+}
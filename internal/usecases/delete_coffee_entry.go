@@ -4,6 +4,7 @@ package usecases
 import (
 	"coffee-tracker-backend/internal/repositories"
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 )
@@ -20,9 +21,9 @@ func NewDeleteCoffeeEntryUseCase(coffeeRepo repositories.CoffeeEntryRepository)
 
 // Execute deletes a coffee entry for a given user
 func (uc *DeleteCoffeeEntryUseCase) Execute(ctx context.Context, userID, entryID uuid.UUID) error {
-	err := uc.coffeeRepo.Delete(ctx, userID, entryID)
+	err := uc.coffeeRepo.Delete(ctx, entryID, userID)
 	if err != nil {
-		if err.Error() == "no coffee entry found with id "+entryID.String()+" for this user" {
+		if errors.Is(err, repositories.ErrNotFound) {
 			return ErrNotFound
 		}
 		return ErrInternalError
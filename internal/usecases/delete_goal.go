@@ -0,0 +1,30 @@
+// file: internal/usecases/delete_goal.go
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type DeleteGoalUseCase struct {
+	goalRepo repositories.GoalRepository
+}
+
+func NewDeleteGoalUseCase(goalRepo repositories.GoalRepository) *DeleteGoalUseCase {
+	return &DeleteGoalUseCase{goalRepo: goalRepo}
+}
+
+func (uc *DeleteGoalUseCase) Execute(ctx context.Context, userID, goalID uuid.UUID) error {
+	err := uc.goalRepo.Delete(ctx, goalID, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrNotFound
+		}
+		return ErrInternalError
+	}
+	return nil
+}
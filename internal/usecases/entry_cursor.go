@@ -0,0 +1,42 @@
+// file: internal/usecases/entry_cursor.go
+package usecases
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Cursors are opaque to clients: base64url("<RFC3339Nano timestamp>|<id>") of the last entry of a page.
+
+func encodeEntryCursor(entry *entities.CoffeeEntry) string {
+	raw := entry.Timestamp.UTC().Format(time.RFC3339Nano) + "|" + entry.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeEntryCursor(cursor string) (*repositories.CoffeeEntryCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidInput
+	}
+
+	tsStr, idStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, ErrInvalidInput
+	}
+	ts, err := time.Parse(time.RFC3339Nano, tsStr)
+	if err != nil {
+		return nil, ErrInvalidInput
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ErrInvalidInput
+	}
+
+	return &repositories.CoffeeEntryCursor{Timestamp: ts, ID: id}, nil
+}
@@ -13,4 +13,7 @@ var (
 	ErrNotFound           	= errors.New("not found")
 	ErrEntryAlreadyExists  	= errors.New("entry already exists")
 	ErrInvalidOTP 			= errors.New("invalid or expired OTP")
+	ErrTooManyAttempts		= errors.New("too many attempts")
+	ErrConfirmationRequired	= errors.New("confirmation token required")
+	ErrInvalidConfirmation	= errors.New("invalid or expired confirmation token")
 )
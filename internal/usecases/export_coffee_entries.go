@@ -0,0 +1,81 @@
+// file: internal/usecases/export_coffee_entries.go
+package usecases
+
+import (
+	"context"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/infrastructure/http/models"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type ExportCoffeeEntriesUseCase struct {
+	coffeeRepo repositories.CoffeeEntryRepository
+	kvRepo     repositories.GenericKVRepository
+}
+
+func NewExportCoffeeEntriesUseCase(coffeeRepo repositories.CoffeeEntryRepository, kvRepo repositories.GenericKVRepository) *ExportCoffeeEntriesUseCase {
+	return &ExportCoffeeEntriesUseCase{
+		coffeeRepo: coffeeRepo,
+		kvRepo:     kvRepo,
+	}
+}
+
+// Execute streams the user's entries in the range to fn, oldest first, with localized type/size names.
+// Entries are never held in memory all at once. An error from fn stops the export and is returned as is,
+// so callers can tell a failed write apart from ErrInvalidInput/ErrInternalError.
+func (uc *ExportCoffeeEntriesUseCase) Execute(ctx context.Context, userID uuid.UUID, q *models.ExportCoffeeEntriesQuery, fn func(*entities.ExportedEntry) error) error {
+	from, to, err := daysRangeUTC(q.From, q.To, q.TzOffset)
+	if err != nil {
+		return err
+	}
+
+	languageCode := q.Language
+	if languageCode == "" {
+		languageCode = defaultLanguageCode
+	}
+	typeNames, err := uc.kvNames(ctx, repositories.KVCoffeeTypes, languageCode)
+	if err != nil {
+		return ErrInternalError
+	}
+	sizeNames, err := uc.kvNames(ctx, repositories.KVCoffeeSizes, languageCode)
+	if err != nil {
+		return ErrInternalError
+	}
+
+	var fnErr error
+	err = uc.coffeeRepo.StreamEntries(ctx, userID, from, to, func(entry *entities.CoffeeEntry) error {
+		exported := &entities.ExportedEntry{CoffeeEntry: entry}
+		if entry.CoffeeTypeID != nil {
+			exported.TypeName = typeNames[*entry.CoffeeTypeID]
+		}
+		if entry.SizeID != nil {
+			exported.SizeName = sizeNames[*entry.SizeID]
+		}
+		fnErr = fn(exported)
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		return ErrInternalError
+	}
+
+	return nil
+}
+
+// kvNames returns the localized names of a KV lookup by id
+func (uc *ExportCoffeeEntriesUseCase) kvNames(ctx context.Context, typeID int, languageCode string) (map[int]string, error) {
+	items, err := uc.kvRepo.GetKV(ctx, typeID, languageCode)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]string, len(items))
+	for _, item := range items {
+		names[item.Key] = item.Value
+	}
+	return names, nil
+}
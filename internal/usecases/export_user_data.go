@@ -0,0 +1,294 @@
+// file: internal/usecases/export_user_data.go
+package usecases
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/infrastructure/storage"
+	"coffee-tracker-backend/internal/infrastructure/utils"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// dataExportTimeout bounds the build of one archive; a pending export older than this was lost
+	// (e.g. to a restart) and a new one can be started
+	dataExportTimeout = 15 * time.Minute
+	// dataExportPageSize is the number of trashed entries read at a time
+	dataExportPageSize = 500
+)
+
+type ExportUserDataUseCase struct {
+	jobsCtx      context.Context // cancelled at shutdown, stops the archives being built
+	userRepo     repositories.UserRepository
+	settingsRepo repositories.UserSettingsRepository
+	coffeeRepo   repositories.CoffeeEntryRepository
+	goalRepo     repositories.GoalRepository
+	authRepo     repositories.AuthRepository
+	exportRepo   repositories.DataExportRepository
+	storage      storage.StorageService
+	exportBucket string        // private bucket the archives are uploaded to
+	avatarBucket string        // bucket of the profile images
+	linkTTL      time.Duration // lifetime of a download link
+}
+
+func NewExportUserDataUseCase(
+	jobsCtx context.Context,
+	userRepo repositories.UserRepository,
+	settingsRepo repositories.UserSettingsRepository,
+	coffeeRepo repositories.CoffeeEntryRepository,
+	goalRepo repositories.GoalRepository,
+	authRepo repositories.AuthRepository,
+	exportRepo repositories.DataExportRepository,
+	storage storage.StorageService,
+	exportBucket, avatarBucket string,
+	linkTTL time.Duration,
+) *ExportUserDataUseCase {
+	return &ExportUserDataUseCase{
+		jobsCtx:      jobsCtx,
+		userRepo:     userRepo,
+		settingsRepo: settingsRepo,
+		coffeeRepo:   coffeeRepo,
+		goalRepo:     goalRepo,
+		authRepo:     authRepo,
+		exportRepo:   exportRepo,
+		storage:      storage,
+		exportBucket: exportBucket,
+		avatarBucket: avatarBucket,
+		linkTTL:      linkTTL,
+	}
+}
+
+// Start records a pending export and builds the archive in the background.
+// Returns ErrConflict while another export of the user is being built.
+func (uc *ExportUserDataUseCase) Start(ctx context.Context, userID uuid.UUID) (*entities.DataExport, error) {
+	now := utils.NowUTC()
+	export := &entities.DataExport{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Status:    entities.DataExportPending,
+		CreatedAt: now,
+	}
+	created, err := uc.exportRepo.CreatePending(ctx, export, now.Add(-dataExportTimeout))
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if !created {
+		return nil, ErrConflict
+	}
+
+	go uc.build(export)
+
+	return export, nil
+}
+
+// Get returns the user's export; a ready export comes with a fresh download link
+func (uc *ExportUserDataUseCase) Get(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*entities.DataExport, error) {
+	export, err := uc.exportRepo.GetByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, ErrInternalError
+	}
+
+	switch export.Status {
+	case entities.DataExportPending:
+		if export.CreatedAt.Before(utils.NowUTC().Add(-dataExportTimeout)) {
+			export.Status, export.Error = entities.DataExportFailed, "export timed out"
+		}
+	case entities.DataExportReady:
+		url, err := uc.storage.GenerateSignedURL(ctx, uc.exportBucket, export.ObjectPath, int(uc.linkTTL.Seconds()))
+		if err != nil {
+			return nil, ErrInternalError
+		}
+		expiry := utils.NowUTC().Add(uc.linkTTL)
+		export.DownloadURL, export.DownloadExpiry = url, &expiry
+	}
+
+	return export, nil
+}
+
+// build writes the archive to a temporary file, uploads it and records the outcome.
+// It runs detached from the request that started it, until the server shuts down.
+func (uc *ExportUserDataUseCase) build(export *entities.DataExport) {
+	ctx, cancel := context.WithTimeout(uc.jobsCtx, dataExportTimeout)
+	defer cancel()
+
+	objectPath := fmt.Sprintf("%s/%s.zip", export.UserID, export.ID)
+	err := uc.buildArchive(ctx, export.UserID, objectPath)
+	if err != nil {
+		log.Printf("data export %s for user %s failed: %v", export.ID, export.UserID, err)
+		// Still record the failure when the build was cut short by the shutdown
+		if err := uc.exportRepo.MarkFailed(context.WithoutCancel(ctx), export.ID, "failed to build the archive"); err != nil {
+			log.Printf("data export %s: failed to record the failure: %v", export.ID, err)
+		}
+		return
+	}
+
+	if err := uc.exportRepo.MarkReady(ctx, export.ID, objectPath); err != nil {
+		log.Printf("data export %s: failed to record completion: %v", export.ID, err)
+	}
+}
+
+func (uc *ExportUserDataUseCase) buildArchive(ctx context.Context, userID uuid.UUID, objectPath string) error {
+	file, err := os.CreateTemp("", "data-export-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if err := uc.writeArchive(ctx, userID, file); err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, err = uc.storage.UploadFile(ctx, uc.exportBucket, objectPath, file, false)
+	return err
+}
+
+// writeArchive writes one file per kind of data: JSON documents, plus the avatar image when there is one
+func (uc *ExportUserDataUseCase) writeArchive(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("profile: %w", err)
+	}
+	if err := writeZipJSON(zw, "profile.json", user); err != nil {
+		return err
+	}
+
+	settings, err := uc.settingsRepo.Get(ctx, userID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("settings: %w", err)
+	}
+	if err := writeZipJSON(zw, "settings.json", entities.NewExportedSettings(settings)); err != nil {
+		return err
+	}
+
+	goals, err := uc.goalRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("goals: %w", err)
+	}
+	if err := writeZipJSON(zw, "goals.json", goals); err != nil {
+		return err
+	}
+
+	sessions, err := uc.authRepo.ListDeviceSessions(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("device sessions: %w", err)
+	}
+	if err := writeZipJSON(zw, "device_sessions.json", sessions); err != nil {
+		return err
+	}
+
+	if err := uc.writeEntries(ctx, zw, userID); err != nil {
+		return fmt.Errorf("entries: %w", err)
+	}
+	if err := uc.writeDeletedEntries(ctx, zw, userID); err != nil {
+		return fmt.Errorf("deleted entries: %w", err)
+	}
+
+	if user.AvatarURL != "" {
+		if err := uc.writeAvatar(ctx, zw, user.AvatarURL); err != nil {
+			return fmt.Errorf("avatar: %w", err)
+		}
+	}
+
+	return zw.Close()
+}
+
+// writeEntries streams the entries as a JSON array, one row at a time
+func (uc *ExportUserDataUseCase) writeEntries(ctx context.Context, zw *zip.Writer, userID uuid.UUID) error {
+	f, err := zw.Create("entries.json")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, "["); err != nil {
+		return err
+	}
+
+	count := 0
+	err = uc.coffeeRepo.StreamEntries(ctx, userID, nil, nil, func(entry *entities.CoffeeEntry) error {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			if _, err := io.WriteString(f, ","); err != nil {
+				return err
+			}
+		}
+		count++
+		_, err = f.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(f, "]")
+	return err
+}
+
+// writeDeletedEntries writes the entries still in the trash
+func (uc *ExportUserDataUseCase) writeDeletedEntries(ctx context.Context, zw *zip.Writer, userID uuid.UUID) error {
+	deleted := make([]*entities.CoffeeEntry, 0)
+	for offset := 0; ; offset += dataExportPageSize {
+		page, err := uc.coffeeRepo.ListDeleted(ctx, userID, dataExportPageSize, offset)
+		if err != nil {
+			return err
+		}
+		deleted = append(deleted, page...)
+		if len(page) < dataExportPageSize {
+			break
+		}
+	}
+	return writeZipJSON(zw, "deleted_entries.json", deleted)
+}
+
+// writeAvatar copies the profile image, found from its public URL, into the archive
+func (uc *ExportUserDataUseCase) writeAvatar(ctx context.Context, zw *zip.Writer, avatarURL string) error {
+	objectPath, ok := avatarObjectPath(avatarURL, uc.avatarBucket)
+	if !ok {
+		return fmt.Errorf("unexpected avatar URL %q", avatarURL)
+	}
+
+	image, err := uc.storage.DownloadFile(ctx, uc.avatarBucket, objectPath)
+	if err != nil {
+		return err
+	}
+	defer image.Close()
+
+	f, err := zw.Create("avatar" + path.Ext(objectPath))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, image)
+	return err
+}
+
+func writeZipJSON(zw *zip.Writer, name string, v any) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -15,12 +15,13 @@ import (
 
 type GenerateOtpUseCase struct {
 	authRepo repositories.AuthRepository
+	attemptRepo repositories.OtpAttemptRepository
 	smsService notifications.SMSService
 	strength  config.OtpStrength
 }
 
-func NewGenerateOtpUseCase(authRepo repositories.AuthRepository, smsService notifications.SMSService, strength config.OtpStrength) *GenerateOtpUseCase {
-	return &GenerateOtpUseCase{authRepo: authRepo, smsService: smsService, strength: strength }
+func NewGenerateOtpUseCase(authRepo repositories.AuthRepository, attemptRepo repositories.OtpAttemptRepository, smsService notifications.SMSService, strength config.OtpStrength) *GenerateOtpUseCase {
+	return &GenerateOtpUseCase{authRepo: authRepo, attemptRepo: attemptRepo, smsService: smsService, strength: strength }
 }
 
 func (uc *GenerateOtpUseCase) Execute(ctx context.Context, userID uuid.UUID, mobile string) (error) {
@@ -40,6 +41,11 @@ func (uc *GenerateOtpUseCase) Execute(ctx context.Context, userID uuid.UUID, mob
 		return err
 	}
 
+	// A new code gets a fresh set of attempts; the per-mobile rate limit bounds how often
+	if err := uc.attemptRepo.Reset(ctx, otpUserAttemptKey(userID)); err != nil {
+		return err
+	}
+
 	// Send SMS here
 	if err := uc.smsService.SendOTP(userID, mobile, otp); err != nil {
 		return fmt.Errorf("failed to send OTP: %w", err)
@@ -0,0 +1,95 @@
+// file: internal/usecases/get_caffeine_curve.go
+package usecases
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+const (
+	caffeineCurveWindow      = 24 * time.Hour // sampled before and after the reference time
+	defaultCurveStepMinutes  = 30
+	minCurveStepMinutes      = 5
+	maxCurveStepMinutes      = 240
+	caffeineLookbackHalfLife = 5 // doses older than 5 half-lives are ~3% and ignored
+)
+
+type GetCaffeineCurveUseCase struct {
+	coffeeRepo   repositories.CoffeeEntryRepository
+	settingsRepo repositories.UserSettingsRepository
+}
+
+func NewGetCaffeineCurveUseCase(coffeeRepo repositories.CoffeeEntryRepository, settingsRepo repositories.UserSettingsRepository) *GetCaffeineCurveUseCase {
+	return &GetCaffeineCurveUseCase{
+		coffeeRepo:   coffeeRepo,
+		settingsRepo: settingsRepo,
+	}
+}
+
+// Execute estimates the caffeine level at the given time and samples the curve
+// for the 24h before and after it, using the user's caffeine half-life.
+func (uc *GetCaffeineCurveUseCase) Execute(ctx context.Context, userID uuid.UUID, at time.Time, stepMinutes int) (*entities.CaffeineCurve, error) {
+	if stepMinutes == 0 {
+		stepMinutes = defaultCurveStepMinutes
+	}
+	if stepMinutes < minCurveStepMinutes || stepMinutes > maxCurveStepMinutes {
+		return nil, ErrInvalidInput
+	}
+
+	halfLifeMinutes := entities.DefaultCaffeineHalfLifeMinutes
+	settings, err := uc.settingsRepo.Get(ctx, userID)
+	switch {
+	case err == nil:
+		if settings.CaffeineHalfLifeMinutes > 0 {
+			halfLifeMinutes = settings.CaffeineHalfLifeMinutes
+		}
+	case errors.Is(err, repositories.ErrNotFound):
+		// no settings row yet, keep the default
+	default:
+		return nil, ErrInternalError
+	}
+	halfLife := time.Duration(halfLifeMinutes) * time.Minute
+
+	curveStart := at.Add(-caffeineCurveWindow)
+	curveEnd := at.Add(caffeineCurveWindow)
+
+	intakes, err := uc.coffeeRepo.GetCaffeineIntakes(ctx, userID, curveStart.Add(-caffeineLookbackHalfLife*halfLife), curveEnd.Add(time.Nanosecond))
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	step := time.Duration(stepMinutes) * time.Minute
+	points := make([]entities.CaffeinePoint, 0, int(2*caffeineCurveWindow/step)+1)
+	for t := curveStart; !t.After(curveEnd); t = t.Add(step) {
+		points = append(points, entities.CaffeinePoint{
+			Time:       t,
+			CaffeineMg: caffeineLevelAt(intakes, t, halfLife),
+		})
+	}
+
+	return &entities.CaffeineCurve{
+		At:              at,
+		HalfLifeMinutes: halfLifeMinutes,
+		CurrentMg:       caffeineLevelAt(intakes, at, halfLife),
+		Points:          points,
+	}, nil
+}
+
+// caffeineLevelAt sums what is left of every dose taken up to t, rounded to 0.1 mg
+func caffeineLevelAt(intakes []entities.CaffeineIntake, t time.Time, halfLife time.Duration) float64 {
+	var total float64
+	for _, intake := range intakes {
+		if intake.Timestamp.After(t) {
+			break // intakes are sorted oldest first
+		}
+		total += entities.CaffeineRemaining(intake.CaffeineMg, t.Sub(intake.Timestamp), halfLife)
+	}
+	return math.Round(total*10) / 10
+}
@@ -6,11 +6,17 @@ import (
 	"time"
 
 	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/infrastructure/http/models"
 	"coffee-tracker-backend/internal/repositories"
 
 	"github.com/google/uuid"
 )
 
+const (
+	defaultEntriesLimit = 50
+	maxEntriesLimit     = 500
+)
+
 type GetCoffeeEntriesUseCase struct {
 	coffeeRepo repositories.CoffeeEntryRepository
 }
@@ -21,46 +27,100 @@ func NewGetCoffeeEntriesUseCase(coffeeRepo repositories.CoffeeEntryRepository) *
 	}
 }
 
-func (uc *GetCoffeeEntriesUseCase) Execute(ctx context.Context, userID uuid.UUID, dateStr *string, tzOffsetMinutes *int, limit, offset int) ([]*entities.CoffeeEntry, error) {
-	if limit <= 0 {
-		limit = 50 // default limit
+// Execute lists the user's entries. Without date/from/to all entries are listed (paginated);
+// from and to are inclusive local days and either one may be omitted for an open-ended range.
+// In cursor mode (q.Cursor set) the returned next cursor is nil on the last page; in offset mode it is always nil.
+func (uc *GetCoffeeEntriesUseCase) Execute(ctx context.Context, userID uuid.UUID, q *models.ListCoffeeEntriesQuery) ([]*entities.CoffeeEntry, *string, error) {
+	filter, err := buildEntryFilter(userID, q)
+	if err != nil {
+		return nil, nil, err
 	}
 
+	if q.Cursor == nil {
+		entries, err := uc.coffeeRepo.List(ctx, filter)
+		if err != nil {
+			return nil, nil, ErrInternalError
+		}
+		// If entries is nil, return empty slice instead
+		if entries == nil {
+			return []*entities.CoffeeEntry{}, nil, nil
+		}
+		return entries, nil, nil
+	}
 
-	//log.Printf("GetCoffeeEntriesUseCase: dateStr=%v, tzOffsetMinutes=%v", *dateStr, *tzOffsetMinutes)
-
-	var parsedTime time.Time
-	var err error
+	// Keyset pages follow the (timestamp, id) order, and can't be mixed with an offset
+	if filter.Sort != repositories.SortTimestampAsc && filter.Sort != repositories.SortTimestampDesc || filter.Offset != 0 {
+		return nil, nil, ErrInvalidInput
+	}
+	if *q.Cursor != "" {
+		if filter.After, err = decodeEntryCursor(*q.Cursor); err != nil {
+			return nil, nil, err
+		}
+	}
 
-	// Parse the base date without timezone
-	baseTime, err := time.Parse("2006-01-02", *dateStr) // For "2025-08-21"
+	// Fetch one extra row to know whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+	entries, err := uc.coffeeRepo.List(ctx, filter)
 	if err != nil {
-		return nil, ErrInvalidInput
+		return nil, nil, ErrInternalError
 	}
 
-	// Handle timezone offset if provided
-	if tzOffsetMinutes != nil {
-		parsedTime = adjustTimeWithOffsetMinutes(baseTime, *tzOffsetMinutes)
-	} else {
-		// Default to UTC if no offset provided
-		parsedTime = baseTime.UTC()
+	var nextCursor *string
+	if len(entries) > limit {
+		entries = entries[:limit]
+		cursor := encodeEntryCursor(entries[limit-1])
+		nextCursor = &cursor
+	}
+	if entries == nil {
+		entries = []*entities.CoffeeEntry{}
 	}
 
-	// Convert to UTC and get start/end of day
-	utcStart := parsedTime.UTC()
-	utcEnd := utcStart.Add(24 * time.Hour) // This is the start of the NEXT day
-	
+	return entries, nextCursor, nil
+}
 
-	entries, err := uc.coffeeRepo.GetByUserIDAndDateRange(ctx, userID, limit, offset, utcStart, utcEnd)
-	if err != nil {
-		return nil, ErrInternalError
+// buildEntryFilter validates the listing query and converts its local days to UTC bounds
+func buildEntryFilter(userID uuid.UUID, q *models.ListCoffeeEntriesQuery) (repositories.CoffeeEntryFilter, error) {
+	filter := repositories.CoffeeEntryFilter{
+		UserID:       userID,
+		CoffeeTypeID: q.CoffeeType,
+		SizeID:       q.Size,
+		HasNotes:     q.HasNotes,
+		HasLocation:  q.HasLocation,
+		Sort:         repositories.SortTimestampAsc,
+		Limit:        q.Limit,
+		Offset:       q.Offset,
 	}
-	// If entries is nil, return empty slice instead
-	if entries == nil {
-		return []*entities.CoffeeEntry{}, nil
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultEntriesLimit
+	}
+	if filter.Limit > maxEntriesLimit || filter.Offset < 0 {
+		return filter, ErrInvalidInput
 	}
 
-	return entries, nil
+	if q.Sort != "" {
+		filter.Sort = repositories.CoffeeEntrySort(q.Sort)
+		if !filter.Sort.IsValid() {
+			return filter, ErrInvalidInput
+		}
+	}
+
+	fromStr, toStr := q.From, q.To
+	if q.Date != "" {
+		if fromStr != "" || toStr != "" {
+			return filter, ErrInvalidInput
+		}
+		fromStr, toStr = q.Date, q.Date
+	}
+
+	from, to, err := daysRangeUTC(fromStr, toStr, q.TzOffset)
+	if err != nil {
+		return filter, err
+	}
+	filter.From, filter.To = from, to
+
+	return filter, nil
 }
 
 // Helper function to adjust time based on timezone offset in minutes
@@ -76,4 +136,4 @@ func adjustTimeWithOffsetMinutes(baseTime time.Time, offsetMinutes int) time.Tim
 		0, 0, 0, 0, // Start of day
 		location,
 	)
-}
\ No newline at end of file
+}
@@ -0,0 +1,79 @@
+// file: internal/usecases/get_coffee_series.go
+package usecases
+
+import (
+	"context"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// maxSeriesBuckets bounds the response size (a bit over a year of daily buckets)
+const maxSeriesBuckets = 400
+
+type GetCoffeeSeriesUseCase struct {
+	coffeeRepo repositories.CoffeeEntryRepository
+}
+
+func NewGetCoffeeSeriesUseCase(coffeeRepo repositories.CoffeeEntryRepository) *GetCoffeeSeriesUseCase {
+	return &GetCoffeeSeriesUseCase{
+		coffeeRepo: coffeeRepo,
+	}
+}
+
+// Execute returns per-bucket totals between two inclusive local dates. The range is widened to whole
+// buckets so the first and last points are not partial, and empty buckets are returned as zeros.
+func (uc *GetCoffeeSeriesUseCase) Execute(ctx context.Context, userID uuid.UUID, fromStr, toStr string, bucket entities.StatsBucket, tz string) (*entities.StatsSeries, error) {
+	if bucket == "" {
+		bucket = entities.BucketDay
+	}
+	if !bucket.IsValid() {
+		return nil, ErrInvalidInput
+	}
+
+	loc, err := loadTimezone(tz)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := resolveDateRange(fromStr, toStr, bucket, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	rangeStart := bucketStart(from, bucket)
+	rangeEnd := nextBucket(bucketStart(to, bucket), bucket)
+
+	points := make([]entities.SeriesPoint, 0)
+	for t := rangeStart; t.Before(rangeEnd); t = nextBucket(t, bucket) {
+		if len(points) == maxSeriesBuckets {
+			return nil, ErrInvalidInput
+		}
+		points = append(points, entities.SeriesPoint{Date: t.Format(dateLayout)})
+	}
+
+	totals, err := uc.coffeeRepo.GetSeries(ctx, userID, rangeStart.UTC(), rangeEnd.UTC(), bucket, loc.String())
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	byDate := make(map[string]entities.SeriesPoint, len(totals))
+	for _, total := range totals {
+		byDate[total.Date] = total
+	}
+	for i := range points {
+		if total, ok := byDate[points[i].Date]; ok {
+			points[i] = total
+		}
+	}
+
+	return &entities.StatsSeries{
+		From:     rangeStart.Format(dateLayout),
+		To:       rangeEnd.AddDate(0, 0, -1).Format(dateLayout),
+		Bucket:   bucket,
+		Timezone: loc.String(),
+		Points:   points,
+	}, nil
+}
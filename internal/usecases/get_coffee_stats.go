@@ -20,8 +20,14 @@ func NewGetCoffeeStatsUseCase(coffeeRepo repositories.CoffeeEntryRepository) *Ge
 	}
 }
 
-func (uc *GetCoffeeStatsUseCase) Execute(ctx context.Context, userID uuid.UUID) (*entities.CoffeeStats, error) {
-	stats, err := uc.coffeeRepo.GetStats(ctx, userID)
+const defaultLanguageCode = "en"
+
+func (uc *GetCoffeeStatsUseCase) Execute(ctx context.Context, userID uuid.UUID, languageCode string) (*entities.CoffeeStats, error) {
+	if languageCode == "" {
+		languageCode = defaultLanguageCode
+	}
+
+	stats, err := uc.coffeeRepo.GetStats(ctx, userID, languageCode)
 	if err != nil {
 		return nil, ErrInternalError
 	}
@@ -0,0 +1,70 @@
+// file: internal/usecases/get_consumption_heatmap.go
+package usecases
+
+import (
+	"context"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type GetConsumptionHeatmapUseCase struct {
+	coffeeRepo repositories.CoffeeEntryRepository
+}
+
+func NewGetConsumptionHeatmapUseCase(coffeeRepo repositories.CoffeeEntryRepository) *GetConsumptionHeatmapUseCase {
+	return &GetConsumptionHeatmapUseCase{
+		coffeeRepo: coffeeRepo,
+	}
+}
+
+// Execute builds the weekday x hour matrix between two inclusive local dates (default: the last 12 weeks)
+func (uc *GetConsumptionHeatmapUseCase) Execute(ctx context.Context, userID uuid.UUID, fromStr, toStr string, tz string) (*entities.ConsumptionHeatmap, error) {
+	loc, err := loadTimezone(tz)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := resolveDateRange(fromStr, toStr, entities.BucketWeek, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	cells, err := uc.coffeeRepo.GetHeatmap(ctx, userID, from.UTC(), to.AddDate(0, 0, 1).UTC(), loc.String())
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	heatmap := &entities.ConsumptionHeatmap{
+		From:     from.Format(dateLayout),
+		To:       to.Format(dateLayout),
+		Timezone: loc.String(),
+		Days:     entities.HeatmapDays,
+	}
+
+	for _, cell := range cells {
+		if cell.ISOWeekday < 1 || cell.ISOWeekday > 7 || cell.Hour < 0 || cell.Hour > 23 {
+			continue
+		}
+		day := cell.ISOWeekday - 1
+		heatmap.Matrix[day][cell.Hour] = cell.Entries
+		heatmap.Total += cell.Entries
+	}
+
+	// Scan in matrix order, so a tie goes to the earliest day of the week, then the earliest hour
+	for day := range heatmap.Matrix {
+		for hour, entries := range heatmap.Matrix[day] {
+			if entries > 0 && (heatmap.Peak == nil || entries > heatmap.Peak.Entries) {
+				heatmap.Peak = &entities.HeatmapPeak{
+					Day:     entities.HeatmapDays[day],
+					Hour:    hour,
+					Entries: entries,
+				}
+			}
+		}
+	}
+
+	return heatmap, nil
+}
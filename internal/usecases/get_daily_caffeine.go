@@ -0,0 +1,40 @@
+// file: internal/usecases/get_daily_caffeine.go
+package usecases
+
+import (
+	"context"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type GetDailyCaffeineUseCase struct {
+	coffeeRepo repositories.CoffeeEntryRepository
+}
+
+func NewGetDailyCaffeineUseCase(coffeeRepo repositories.CoffeeEntryRepository) *GetDailyCaffeineUseCase {
+	return &GetDailyCaffeineUseCase{
+		coffeeRepo: coffeeRepo,
+	}
+}
+
+// Execute sums the caffeine of a user's local day. An empty dateStr means today.
+func (uc *GetDailyCaffeineUseCase) Execute(ctx context.Context, userID uuid.UUID, dateStr string, tzOffsetMinutes *int) (*entities.DailyCaffeine, error) {
+	utcStart, utcEnd, err := dayRangeUTC(dateStr, tzOffsetMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	totalCaffeine, entries, err := uc.coffeeRepo.GetCaffeineTotal(ctx, userID, utcStart, utcEnd)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	return &entities.DailyCaffeine{
+		Date:          utcStart.In(userLocation(tzOffsetMinutes)).Format(dateLayout),
+		TotalCaffeine: totalCaffeine,
+		Entries:       entries,
+	}, nil
+}
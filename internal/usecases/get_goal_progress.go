@@ -0,0 +1,151 @@
+// file: internal/usecases/get_goal_progress.go
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/infrastructure/utils"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// maxGoalHistoryDays bounds how far back streaks are computed
+const maxGoalHistoryDays = 366
+
+type GetGoalProgressUseCase struct {
+	goalRepo   repositories.GoalRepository
+	coffeeRepo repositories.CoffeeEntryRepository
+}
+
+func NewGetGoalProgressUseCase(goalRepo repositories.GoalRepository, coffeeRepo repositories.CoffeeEntryRepository) *GetGoalProgressUseCase {
+	return &GetGoalProgressUseCase{
+		goalRepo:   goalRepo,
+		coffeeRepo: coffeeRepo,
+	}
+}
+
+// Execute computes the current streak, longest streak and today's status of each of the user's goals,
+// counting days in the user's timezone from the day the goal was created.
+func (uc *GetGoalProgressUseCase) Execute(ctx context.Context, userID uuid.UUID, tz string) ([]*entities.GoalProgress, error) {
+	loc, err := loadTimezone(tz)
+	if err != nil {
+		return nil, err
+	}
+
+	goals, err := uc.goalRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	progress := make([]*entities.GoalProgress, 0, len(goals))
+	if len(goals) == 0 {
+		return progress, nil
+	}
+
+	today := startOfDay(utils.NowUTC(), loc)
+	earliest := today.AddDate(0, 0, -maxGoalHistoryDays)
+
+	goalStart := func(goal *entities.Goal) time.Time {
+		start := startOfDay(goal.CreatedAt, loc)
+		if start.Before(earliest) {
+			start = earliest
+		}
+		return bucketStart(start, goal.Type.StreakUnit())
+	}
+
+	historyStart := today
+	for _, goal := range goals {
+		if start := goalStart(goal); start.Before(historyStart) {
+			historyStart = start
+		}
+	}
+
+	counts, err := uc.coffeeRepo.GetDailyCounts(ctx, userID, historyStart.UTC(), today.AddDate(0, 0, 1).UTC(), loc.String())
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	byDate := make(map[string]entities.DailyCount, len(counts))
+	for _, count := range counts {
+		byDate[count.Date] = count
+	}
+
+	for _, goal := range goals {
+		switch goal.Type {
+		case entities.GoalCaffeineFreeDaysPerWeek:
+			progress = append(progress, weeklyGoalProgress(goal, byDate, goalStart(goal), today))
+		default:
+			progress = append(progress, dailyGoalProgress(goal, byDate, goalStart(goal), today))
+		}
+	}
+
+	return progress, nil
+}
+
+// dailyGoalProgress counts days with at most Target cups. Today counts once it is within the goal so far.
+func dailyGoalProgress(goal *entities.Goal, byDate map[string]entities.DailyCount, start, today time.Time) *entities.GoalProgress {
+	run, longest := 0, 0
+	for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		if byDate[day.Format(dateLayout)].Entries <= goal.Target {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+
+	cupsToday := byDate[today.Format(dateLayout)].Entries
+	return &entities.GoalProgress{
+		Goal:          goal,
+		StreakUnit:    entities.BucketDay,
+		CurrentStreak: run,
+		LongestStreak: longest,
+		Current: entities.GoalPeriodStatus{
+			Date:        today.Format(dateLayout),
+			PeriodStart: today.Format(dateLayout),
+			CupsToday:   cupsToday,
+			Met:         cupsToday <= goal.Target,
+		},
+	}
+}
+
+// weeklyGoalProgress counts weeks with at least Target caffeine-free days. The current week only
+// extends the streak once it is met; until then it does not break it either.
+func weeklyGoalProgress(goal *entities.Goal, byDate map[string]entities.DailyCount, start, today time.Time) *entities.GoalProgress {
+	currentWeek := bucketStart(today, entities.BucketWeek)
+	run, longest, freeDays := 0, 0, 0
+	met := false
+
+	for week := start; !week.After(currentWeek); week = week.AddDate(0, 0, 7) {
+		freeDays = 0
+		for day := week; day.Before(week.AddDate(0, 0, 7)) && !day.After(today); day = day.AddDate(0, 0, 1) {
+			if byDate[day.Format(dateLayout)].CaffeinatedEntries == 0 {
+				freeDays++
+			}
+		}
+
+		met = freeDays >= goal.Target
+		switch {
+		case met:
+			run++
+			longest = max(longest, run)
+		case !week.Equal(currentWeek):
+			run = 0
+		}
+	}
+
+	return &entities.GoalProgress{
+		Goal:          goal,
+		StreakUnit:    entities.BucketWeek,
+		CurrentStreak: run,
+		LongestStreak: longest,
+		Current: entities.GoalPeriodStatus{
+			Date:             today.Format(dateLayout),
+			PeriodStart:      currentWeek.Format(dateLayout),
+			CupsToday:        byDate[today.Format(dateLayout)].Entries,
+			CaffeineFreeDays: &freeDays,
+			Met:              met,
+		},
+	}
+}
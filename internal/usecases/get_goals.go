@@ -0,0 +1,27 @@
+// file: internal/usecases/get_goals.go
+package usecases
+
+import (
+	"context"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type GetGoalsUseCase struct {
+	goalRepo repositories.GoalRepository
+}
+
+func NewGetGoalsUseCase(goalRepo repositories.GoalRepository) *GetGoalsUseCase {
+	return &GetGoalsUseCase{goalRepo: goalRepo}
+}
+
+func (uc *GetGoalsUseCase) Execute(ctx context.Context, userID uuid.UUID) ([]*entities.Goal, error) {
+	goals, err := uc.goalRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	return goals, nil
+}
@@ -0,0 +1,111 @@
+// file: internal/usecases/get_spending_report.go
+package usecases
+
+import (
+	"context"
+	"sort"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type GetSpendingReportUseCase struct {
+	coffeeRepo repositories.CoffeeEntryRepository
+}
+
+func NewGetSpendingReportUseCase(coffeeRepo repositories.CoffeeEntryRepository) *GetSpendingReportUseCase {
+	return &GetSpendingReportUseCase{
+		coffeeRepo: coffeeRepo,
+	}
+}
+
+// Execute returns spending per bucket and currency between two inclusive local dates, plus the
+// per-currency totals of the whole range. Buckets follow the same rules as the stats series.
+func (uc *GetSpendingReportUseCase) Execute(ctx context.Context, userID uuid.UUID, fromStr, toStr string, bucket entities.StatsBucket, tz string) (*entities.SpendingReport, error) {
+	if bucket == "" {
+		bucket = entities.BucketMonth
+	}
+	if !bucket.IsValid() {
+		return nil, ErrInvalidInput
+	}
+
+	loc, err := loadTimezone(tz)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := resolveDateRange(fromStr, toStr, bucket, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	rangeStart := bucketStart(from, bucket)
+	rangeEnd := nextBucket(bucketStart(to, bucket), bucket)
+
+	periods := make([]entities.SpendingPeriod, 0)
+	for t := rangeStart; t.Before(rangeEnd); t = nextBucket(t, bucket) {
+		if len(periods) == maxSeriesBuckets {
+			return nil, ErrInvalidInput
+		}
+		periods = append(periods, entities.SpendingPeriod{
+			Date:       t.Format(dateLayout),
+			Currencies: make([]entities.CurrencySpending, 0),
+		})
+	}
+
+	spent, err := uc.coffeeRepo.GetSpending(ctx, userID, rangeStart.UTC(), rangeEnd.UTC(), bucket, loc.String())
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	byDate := make(map[string][]entities.CurrencySpending, len(spent))
+	for _, period := range spent {
+		byDate[period.Date] = period.Currencies
+	}
+
+	totalsByCurrency := make(map[string]*entities.CurrencySpending)
+	for i := range periods {
+		currencies, ok := byDate[periods[i].Date]
+		if !ok {
+			continue
+		}
+		for j := range currencies {
+			c := &currencies[j]
+			total, ok := totalsByCurrency[c.Currency]
+			if !ok {
+				total = &entities.CurrencySpending{Currency: c.Currency}
+				totalsByCurrency[c.Currency] = total
+			}
+			total.Total += c.Total
+			total.Entries += c.Entries
+
+			c.Total = entities.RoundMoney(c.Total)
+			c.AveragePerCup = entities.RoundMoney(c.Total / float64(c.Entries))
+		}
+		periods[i].Currencies = currencies
+	}
+
+	totals := make([]entities.CurrencySpending, 0, len(totalsByCurrency))
+	for _, total := range totalsByCurrency {
+		total.AveragePerCup = entities.RoundMoney(total.Total / float64(total.Entries))
+		total.Total = entities.RoundMoney(total.Total)
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Entries != totals[j].Entries {
+			return totals[i].Entries > totals[j].Entries
+		}
+		return totals[i].Currency < totals[j].Currency
+	})
+
+	return &entities.SpendingReport{
+		From:     rangeStart.Format(dateLayout),
+		To:       rangeEnd.AddDate(0, 0, -1).Format(dateLayout),
+		Bucket:   bucket,
+		Timezone: loc.String(),
+		Totals:   totals,
+		Periods:  periods,
+	}, nil
+}
@@ -0,0 +1,109 @@
+// file: internal/usecases/get_sync_changes.go
+package usecases
+
+import (
+	"context"
+	"strconv"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSyncPageSize = 200
+	maxSyncPageSize     = 1000
+)
+
+type GetSyncChangesUseCase struct {
+	coffeeRepo repositories.CoffeeEntryRepository
+}
+
+func NewGetSyncChangesUseCase(coffeeRepo repositories.CoffeeEntryRepository) *GetSyncChangesUseCase {
+	return &GetSyncChangesUseCase{
+		coffeeRepo: coffeeRepo,
+	}
+}
+
+// Execute returns the entries created, updated and deleted after the since token, oldest change
+// first. An empty token returns everything from the beginning (a full initial sync).
+func (uc *GetSyncChangesUseCase) Execute(ctx context.Context, userID uuid.UUID, since string, limit int) (*entities.SyncChanges, error) {
+	sinceVersion, err := parseSyncToken(since)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultSyncPageSize
+	}
+	if limit > maxSyncPageSize {
+		return nil, ErrInvalidInput
+	}
+
+	// Fetch one extra row from each feed to know whether there is a next page. Holding the user's
+	// lock waits for writes still in flight and keeps both feeds at the same point of the change order.
+	var entries []*entities.CoffeeEntry
+	var tombstones []entities.EntryTombstone
+	err = uc.coffeeRepo.WithinTx(ctx, func(tx repositories.CoffeeEntryRepository) error {
+		if err := tx.LockUser(ctx, userID); err != nil {
+			return err
+		}
+		if entries, err = tx.GetChangedSince(ctx, userID, sinceVersion, limit+1); err != nil {
+			return err
+		}
+		tombstones, err = tx.GetTombstonesSince(ctx, userID, sinceVersion, limit+1)
+		return err
+	})
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	changes := &entities.SyncChanges{
+		Created: make([]*entities.CoffeeEntry, 0),
+		Updated: make([]*entities.CoffeeEntry, 0),
+		Deleted: make([]entities.EntryTombstone, 0),
+	}
+	lastVersion := sinceVersion
+
+	// Merge both feeds by version, so the page ends at a single position in the change order
+	i, j := 0, 0
+	for taken := 0; taken < limit && (i < len(entries) || j < len(tombstones)); taken++ {
+		if j == len(tombstones) || (i < len(entries) && entries[i].Version < tombstones[j].Version) {
+			entry := entries[i]
+			if entry.CreatedVersion > sinceVersion {
+				changes.Created = append(changes.Created, entry)
+			} else {
+				changes.Updated = append(changes.Updated, entry)
+			}
+			lastVersion = entry.Version
+			i++
+		} else {
+			changes.Deleted = append(changes.Deleted, tombstones[j])
+			lastVersion = tombstones[j].Version
+			j++
+		}
+	}
+
+	changes.HasMore = i < len(entries) || j < len(tombstones)
+	changes.NextToken = formatSyncToken(lastVersion)
+
+	return changes, nil
+}
+
+// Sync tokens are the last change feed version seen by the client; clients treat them as opaque.
+
+func parseSyncToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	version, err := strconv.ParseInt(token, 10, 64)
+	if err != nil || version < 0 {
+		return 0, ErrInvalidInput
+	}
+	return version, nil
+}
+
+func formatSyncToken(version int64) string {
+	return strconv.FormatInt(version, 10)
+}
@@ -0,0 +1,41 @@
+// file: internal/usecases/get_trash.go
+package usecases
+
+import (
+	"context"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type GetTrashUseCase struct {
+	coffeeRepo repositories.CoffeeEntryRepository
+}
+
+func NewGetTrashUseCase(coffeeRepo repositories.CoffeeEntryRepository) *GetTrashUseCase {
+	return &GetTrashUseCase{
+		coffeeRepo: coffeeRepo,
+	}
+}
+
+// Execute lists the user's deleted entries that were not purged yet, most recently deleted first
+func (uc *GetTrashUseCase) Execute(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entities.CoffeeEntry, error) {
+	if limit <= 0 {
+		limit = defaultEntriesLimit
+	}
+	if limit > maxEntriesLimit || offset < 0 {
+		return nil, ErrInvalidInput
+	}
+
+	entries, err := uc.coffeeRepo.ListDeleted(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if entries == nil {
+		return []*entities.CoffeeEntry{}, nil
+	}
+
+	return entries, nil
+}
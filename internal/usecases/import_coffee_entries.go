@@ -0,0 +1,406 @@
+// file: internal/usecases/import_coffee_entries.go
+package usecases
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/infrastructure/http/models"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// MaxImportRows bounds the size of a single import
+const MaxImportRows = 10000
+
+// importTimestampLayouts are the accepted timestamp formats, tried in order.
+// Layouts without a zone are read in the import's timezone.
+var importTimestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// EntryImporter reads one upload format into import rows. Rows map a source column to its value;
+// the request mapping (or the importer's default mapping) says which column fills which entry field.
+type EntryImporter interface {
+	// Read parses the upload. columns is the set of columns every row has, used to report a missing
+	// mapped column upfront, or nil when rows may have different columns.
+	Read(data io.Reader) (columns map[string]bool, records []map[string]string, err error)
+	// DefaultMapping maps entry fields (entities.ImportField*) to the format's own columns, for the
+	// fields the request doesn't map. Fields in neither use a column of their own name.
+	DefaultMapping() map[string]string
+}
+
+type ImportCoffeeEntriesUseCase struct {
+	coffeeRepo repositories.CoffeeEntryRepository
+	kvRepo     repositories.GenericKVRepository
+	importers  map[string]EntryImporter
+}
+
+// NewImportCoffeeEntriesUseCase supports the csv and json formats; more are added with RegisterImporter
+func NewImportCoffeeEntriesUseCase(coffeeRepo repositories.CoffeeEntryRepository, kvRepo repositories.GenericKVRepository) *ImportCoffeeEntriesUseCase {
+	uc := &ImportCoffeeEntriesUseCase{
+		coffeeRepo: coffeeRepo,
+		kvRepo:     kvRepo,
+		importers:  make(map[string]EntryImporter),
+	}
+	uc.RegisterImporter("csv", csvImporter{})
+	uc.RegisterImporter("json", jsonImporter{})
+	return uc
+}
+
+// RegisterImporter makes an upload format available under the given name, replacing any importer
+// registered under it before. Not safe to call while imports are running.
+func (uc *ImportCoffeeEntriesUseCase) RegisterImporter(format string, importer EntryImporter) {
+	uc.importers[format] = importer
+}
+
+// Execute validates every row of the upload and, unless it is a dry run, imports them all in one
+// transaction. When any row (or the file itself) is invalid nothing is imported, and ErrInvalidInput
+// is returned along with the report listing the problems.
+func (uc *ImportCoffeeEntriesUseCase) Execute(ctx context.Context, userID uuid.UUID, req *models.ImportCoffeeEntriesRequest) (*entities.ImportReport, error) {
+	report := &entities.ImportReport{DryRun: req.DryRun, Errors: []entities.ImportRowError{}}
+	fileError := func(field, format string, args ...any) (*entities.ImportReport, error) {
+		report.Errors = append(report.Errors, entities.ImportRowError{Field: field, Message: fmt.Sprintf(format, args...)})
+		return report, ErrInvalidInput
+	}
+
+	importer, ok := uc.importers[req.Format]
+	if !ok {
+		return fileError("", "unsupported format %q", req.Format)
+	}
+
+	defaults := importer.DefaultMapping()
+	columns, err := importColumns(defaults, req.Mapping)
+	if err != nil {
+		return fileError("", "%v", err)
+	}
+
+	header, records, err := importer.Read(req.Data)
+	if err != nil {
+		return fileError("", "%v", err)
+	}
+	if len(records) > MaxImportRows {
+		return fileError("", "too many rows (at most %d)", MaxImportRows)
+	}
+	// A header tells upfront whether a mapped column is missing
+	if header != nil {
+		for _, field := range entities.ImportFields {
+			_, mapped := req.Mapping[field]
+			_, mappedByDefault := defaults[field]
+			if (mapped || mappedByDefault || field == entities.ImportFieldTimestamp) && !header[columns[field]] {
+				return fileError(field, "column %q not found", columns[field])
+			}
+		}
+	}
+
+	languageCode := req.Language
+	if languageCode == "" {
+		languageCode = defaultLanguageCode
+	}
+	typeIDs, err := uc.kvIDs(ctx, repositories.KVCoffeeTypes, languageCode)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	sizeIDs, err := uc.kvIDs(ctx, repositories.KVCoffeeSizes, languageCode)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	parser := importRowParser{
+		columns: columns,
+		loc:     userLocation(req.TzOffset),
+		typeIDs: typeIDs,
+		sizeIDs: sizeIDs,
+	}
+	entries := make([]*entities.CoffeeEntry, 0, len(records))
+	for i, record := range records {
+		row := i + 1
+		createReq, rowErrors := parser.parse(row, record)
+		if len(rowErrors) > 0 {
+			report.Errors = append(report.Errors, rowErrors...)
+			continue
+		}
+
+		entry, err := newCoffeeEntry(ctx, uc.kvRepo, userID, createReq)
+		if err == ErrInvalidInput {
+			report.Errors = append(report.Errors, entities.ImportRowError{Row: row, Message: "invalid price, currency or caffeine"})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	report.Rows = len(records)
+	report.Valid = len(entries)
+	if len(report.Errors) > 0 {
+		return report, ErrInvalidInput
+	}
+	if req.DryRun || len(entries) == 0 {
+		return report, nil
+	}
+
+	err = uc.coffeeRepo.WithinTx(ctx, func(tx repositories.CoffeeEntryRepository) error {
+		return tx.CreateMany(ctx, userID, entries)
+	})
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	report.Imported = len(entries)
+
+	return report, nil
+}
+
+// kvIDs returns the ids of a KV lookup by lower-cased localized name
+func (uc *ImportCoffeeEntriesUseCase) kvIDs(ctx context.Context, typeID int, languageCode string) (map[string]int, error) {
+	items, err := uc.kvRepo.GetKV(ctx, typeID, languageCode)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]int, 2*len(items))
+	for _, item := range items {
+		ids[strings.ToLower(strings.TrimSpace(item.Value))] = item.Key
+		ids[strconv.Itoa(item.Key)] = item.Key
+	}
+	return ids, nil
+}
+
+// importColumns resolves the source column of every entry field: the request mapping wins over
+// the importer defaults, and fields in neither use their own name
+func importColumns(defaults, mapping map[string]string) (map[string]string, error) {
+	columns := make(map[string]string, len(entities.ImportFields))
+	for _, field := range entities.ImportFields {
+		columns[field] = field
+		if column, ok := defaults[field]; ok {
+			columns[field] = column
+		}
+	}
+	for field, column := range mapping {
+		if _, ok := columns[field]; !ok {
+			return nil, fmt.Errorf("unknown field %q in mapping", field)
+		}
+		if column == "" {
+			return nil, fmt.Errorf("empty column for field %q in mapping", field)
+		}
+		columns[field] = column
+	}
+	return columns, nil
+}
+
+// csvImporter reads a CSV file with a header row
+type csvImporter struct{}
+
+func (csvImporter) DefaultMapping() map[string]string { return nil }
+
+func (csvImporter) Read(data io.Reader) (map[string]bool, []map[string]string, error) {
+	reader := csv.NewReader(data)
+	reader.FieldsPerRecord = -1 // spreadsheet exports often drop trailing empty cells
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("empty file")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+	columns := make(map[string]bool, len(header))
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+		columns[header[i]] = true
+	}
+
+	var records []map[string]string
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		if len(records) == MaxImportRows {
+			return nil, nil, fmt.Errorf("too many rows (at most %d)", MaxImportRows)
+		}
+
+		record := make(map[string]string, len(header))
+		for i, column := range header {
+			if i < len(values) {
+				record[column] = values[i]
+			}
+		}
+		records = append(records, record)
+	}
+	return columns, records, nil
+}
+
+// jsonImporter reads an array of objects; numbers and booleans are kept in their JSON spelling.
+// Objects may each have their own keys, so there is no common set of columns.
+type jsonImporter struct{}
+
+func (jsonImporter) DefaultMapping() map[string]string { return nil }
+
+func (jsonImporter) Read(data io.Reader) (map[string]bool, []map[string]string, error) {
+	decoder := json.NewDecoder(data)
+	decoder.UseNumber()
+
+	var objects []map[string]any
+	if err := decoder.Decode(&objects); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON, expected an array of objects: %v", err)
+	}
+	if len(objects) > MaxImportRows {
+		return nil, nil, fmt.Errorf("too many rows (at most %d)", MaxImportRows)
+	}
+
+	records := make([]map[string]string, len(objects))
+	for i, object := range objects {
+		record := make(map[string]string, len(object))
+		for key, value := range object {
+			switch v := value.(type) {
+			case nil:
+			case string:
+				record[key] = v
+			case json.Number, bool:
+				record[key] = fmt.Sprint(v)
+			default:
+				return nil, nil, fmt.Errorf("row %d: %q must be a string or a number", i+1, key)
+			}
+		}
+		records[i] = record
+	}
+	return nil, records, nil
+}
+
+// importRowParser turns one import row into a create request
+type importRowParser struct {
+	columns map[string]string
+	loc     *time.Location
+	typeIDs map[string]int
+	sizeIDs map[string]int
+}
+
+// parse reads the row's fields, collecting a problem for every field it can't read
+func (p *importRowParser) parse(row int, record map[string]string) (*models.CreateCoffeeEntryRequest, []entities.ImportRowError) {
+	var errs []entities.ImportRowError
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, entities.ImportRowError{Row: row, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	value := func(field string) string {
+		return strings.TrimSpace(record[p.columns[field]])
+	}
+	optionalNumber := func(field string, parse func(string) error) {
+		if v := value(field); v != "" {
+			if err := parse(v); err != nil {
+				fail(field, "%q is not a number", v)
+			}
+		}
+	}
+
+	req := &models.CreateCoffeeEntryRequest{}
+
+	if v := value(entities.ImportFieldTimestamp); v == "" {
+		fail(entities.ImportFieldTimestamp, "timestamp is required")
+	} else if ts, ok := parseImportTimestamp(v, p.loc); !ok {
+		fail(entities.ImportFieldTimestamp, "unrecognized timestamp %q", v)
+	} else {
+		req.Timestamp = ts
+	}
+
+	if v := value(entities.ImportFieldType); v != "" {
+		if id, ok := p.typeIDs[strings.ToLower(v)]; ok {
+			req.CoffeeType = &id
+		} else {
+			fail(entities.ImportFieldType, "unknown coffee type %q", v)
+		}
+	}
+	if v := value(entities.ImportFieldSize); v != "" {
+		if id, ok := p.sizeIDs[strings.ToLower(v)]; ok {
+			req.Size = &id
+		} else {
+			fail(entities.ImportFieldSize, "unknown coffee size %q", v)
+		}
+	}
+
+	if v := value(entities.ImportFieldNotes); v != "" {
+		req.Notes = &v
+	}
+	if v := value(entities.ImportFieldCurrency); v != "" {
+		req.Currency = &v
+	}
+
+	optionalNumber(entities.ImportFieldLatitude, func(v string) (err error) {
+		req.Latitude, err = parseOptionalFloat(v)
+		return err
+	})
+	optionalNumber(entities.ImportFieldLongitude, func(v string) (err error) {
+		req.Longitude, err = parseOptionalFloat(v)
+		return err
+	})
+	optionalNumber(entities.ImportFieldCaffeine, func(v string) (err error) {
+		req.Caffeine, err = parseOptionalInt(v)
+		return err
+	})
+	optionalNumber(entities.ImportFieldPrice, func(v string) (err error) {
+		req.Price, err = parseOptionalFloat(v)
+		return err
+	})
+	optionalNumber(entities.ImportFieldRating, func(v string) (err error) {
+		req.Rating, err = parseOptionalInt(v)
+		return err
+	})
+
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		fail(entities.ImportFieldLatitude, "latitude and longitude must be set together")
+	} else if req.Latitude != nil && (*req.Latitude < -90 || *req.Latitude > 90 || *req.Longitude < -180 || *req.Longitude > 180) {
+		fail(entities.ImportFieldLatitude, "coordinates out of range")
+	}
+	if !isValidRating(req.Rating) {
+		fail(entities.ImportFieldRating, "rating must be between %d and %d", entities.MinRating, entities.MaxRating)
+	}
+
+	return req, errs
+}
+
+// parseImportTimestamp tries each accepted layout; zone-less timestamps are read in loc
+func parseImportTimestamp(v string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range importTimestampLayouts {
+		if ts, err := time.ParseInLocation(layout, v, loc); err == nil {
+			return ts.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseOptionalInt(v string) (*int, error) {
+	parsed, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func parseOptionalFloat(v string) (*float64, error) {
+	parsed, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, err
+	}
+	if math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+		return nil, strconv.ErrSyntax
+	}
+	return &parsed, nil
+}
@@ -0,0 +1,26 @@
+// file: internal/usecases/price.go
+package usecases
+
+import (
+	"coffee-tracker-backend/internal/entities"
+)
+
+// validatePrice checks an optional price/currency pair and returns the normalized currency.
+// A price requires a currency and vice versa; both nil means the price is unknown.
+func validatePrice(price *float64, currency *string) (*string, error) {
+	if price == nil && currency == nil {
+		return nil, nil
+	}
+	if price == nil || currency == nil {
+		return nil, ErrInvalidInput
+	}
+	if *price < 0 || *price > entities.MaxPricePerEntry {
+		return nil, ErrInvalidInput
+	}
+
+	code, ok := entities.NormalizeCurrency(*currency)
+	if !ok {
+		return nil, ErrInvalidInput
+	}
+	return &code, nil
+}
@@ -0,0 +1,77 @@
+// file: internal/usecases/purge_deleted_accounts.go
+package usecases
+
+import (
+	"context"
+	"log"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/infrastructure/storage"
+	"coffee-tracker-backend/internal/infrastructure/utils"
+	"coffee-tracker-backend/internal/repositories"
+)
+
+// purgeAccountsBatchSize is the number of accounts purged per run
+const purgeAccountsBatchSize = 100
+
+type PurgeDeletedAccountsUseCase struct {
+	userRepo     repositories.UserRepository
+	exportRepo   repositories.DataExportRepository
+	storage      storage.StorageService
+	avatarBucket string
+	exportBucket string
+}
+
+func NewPurgeDeletedAccountsUseCase(
+	userRepo repositories.UserRepository,
+	exportRepo repositories.DataExportRepository,
+	storage storage.StorageService,
+	avatarBucket, exportBucket string,
+) *PurgeDeletedAccountsUseCase {
+	return &PurgeDeletedAccountsUseCase{
+		userRepo:     userRepo,
+		exportRepo:   exportRepo,
+		storage:      storage,
+		avatarBucket: avatarBucket,
+		exportBucket: exportBucket,
+	}
+}
+
+// Execute permanently removes the deleted accounts whose grace period is over, and returns how
+// many were removed. An account that fails is logged and retried on the next run.
+func (uc *PurgeDeletedAccountsUseCase) Execute(ctx context.Context) (int, error) {
+	users, err := uc.userRepo.ListDueForDeletion(ctx, utils.NowUTC(), purgeAccountsBatchSize)
+	if err != nil {
+		return 0, ErrInternalError
+	}
+
+	purged := 0
+	for _, user := range users {
+		if err := uc.purge(ctx, user); err != nil {
+			log.Printf("purging deleted account %s failed: %v", user.ID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// purge removes the stored files before the rows that point at them, so a failure leaves
+// nothing behind that a later run could not find
+func (uc *PurgeDeletedAccountsUseCase) purge(ctx context.Context, user *entities.User) error {
+	if objectPath, ok := avatarObjectPath(user.AvatarURL, uc.avatarBucket); ok {
+		if err := uc.storage.DeleteFiles(ctx, uc.avatarBucket, objectPath); err != nil {
+			return err
+		}
+	}
+
+	archives, err := uc.exportRepo.ListObjectPaths(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if err := uc.storage.DeleteFiles(ctx, uc.exportBucket, archives...); err != nil {
+		return err
+	}
+
+	return uc.userRepo.Delete(ctx, user.ID)
+}
@@ -0,0 +1,32 @@
+// file: internal/usecases/purge_deleted_entries.go
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"coffee-tracker-backend/internal/infrastructure/utils"
+	"coffee-tracker-backend/internal/repositories"
+)
+
+type PurgeDeletedEntriesUseCase struct {
+	coffeeRepo repositories.CoffeeEntryRepository
+	retention  time.Duration
+}
+
+func NewPurgeDeletedEntriesUseCase(coffeeRepo repositories.CoffeeEntryRepository, retention time.Duration) *PurgeDeletedEntriesUseCase {
+	return &PurgeDeletedEntriesUseCase{
+		coffeeRepo: coffeeRepo,
+		retention:  retention,
+	}
+}
+
+// Execute permanently removes the entries that have been in the trash for longer than the
+// retention period, and returns how many were removed.
+func (uc *PurgeDeletedEntriesUseCase) Execute(ctx context.Context) (int64, error) {
+	purged, err := uc.coffeeRepo.PurgeDeleted(ctx, utils.NowUTC().Add(-uc.retention))
+	if err != nil {
+		return 0, ErrInternalError
+	}
+	return purged, nil
+}
@@ -0,0 +1,9 @@
+// file: internal/usecases/rating.go
+package usecases
+
+import "coffee-tracker-backend/internal/entities"
+
+// isValidRating accepts a missing rating or one on the 1-5 scale
+func isValidRating(rating *int) bool {
+	return rating == nil || (*rating >= entities.MinRating && *rating <= entities.MaxRating)
+}
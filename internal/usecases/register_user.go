@@ -0,0 +1,89 @@
+// file: internal/usecases/register_user.go
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/infrastructure/utils"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type RegisterUserUseCase struct {
+	userRepo      repositories.UserRepository
+	generateOtpUC *GenerateOtpUseCase
+}
+
+func NewRegisterUserUseCase(userRepo repositories.UserRepository, generateOtpUC *GenerateOtpUseCase) *RegisterUserUseCase {
+	return &RegisterUserUseCase{userRepo: userRepo, generateOtpUC: generateOtpUC}
+}
+
+// Execute creates a pending user for a new mobile number and sends it an OTP; verifying the OTP
+// activates the user. A number whose sign-up was never verified gets a new OTP.
+// Returns ErrConflict when the number already belongs to a user.
+func (uc *RegisterUserUseCase) Execute(ctx context.Context, mobile, name string) error {
+	mobile = strings.TrimSpace(mobile)
+	if !isValidMobile(mobile) {
+		return ErrInvalidInput
+	}
+
+	user, err := uc.userRepo.GetByMobile(ctx, mobile)
+	switch {
+	case err == nil:
+		if user.StatusID != entities.StatusPending {
+			return ErrConflict
+		}
+	case errors.Is(err, repositories.ErrNotFound):
+		now := utils.NowUTC()
+		user = &entities.User{
+			ID:        uuid.New(),
+			Mobile:    mobile,
+			Name:      strings.TrimSpace(name),
+			StatusID:  entities.StatusPending,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := uc.userRepo.Create(ctx, user); err != nil {
+			return ErrInternalError
+		}
+	default:
+		return ErrInternalError
+	}
+
+	return uc.generateOtpUC.Execute(ctx, user.ID, mobile)
+}
+
+// isValidMobile accepts 7 to 15 digits, optionally in international format (+...)
+func isValidMobile(mobile string) bool {
+	digits := strings.TrimPrefix(mobile, "+")
+	if len(digits) < 7 || len(digits) > 15 {
+		return false
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+type ActivateUserUseCase struct {
+	userRepo repositories.UserRepository
+}
+
+func NewActivateUserUseCase(userRepo repositories.UserRepository) *ActivateUserUseCase {
+	return &ActivateUserUseCase{userRepo: userRepo}
+}
+
+// Execute activates a pending user once their mobile number is verified.
+// A user that is no longer pending (e.g. activated by a concurrent login) is left as is.
+func (uc *ActivateUserUseCase) Execute(ctx context.Context, userID uuid.UUID) error {
+	if _, err := uc.userRepo.Activate(ctx, userID); err != nil {
+		return ErrInternalError
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// file: internal/usecases/register_user_test.go
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/infrastructure/config"
+	"coffee-tracker-backend/internal/infrastructure/notifications"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// memoryUserRepo keeps users in a map; the methods the tests don't use panic (nil interface)
+type memoryUserRepo struct {
+	repositories.UserRepository
+	users map[uuid.UUID]entities.User
+}
+
+func (r *memoryUserRepo) Create(ctx context.Context, user *entities.User) error {
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *memoryUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	if user, ok := r.users[id]; ok {
+		return &user, nil
+	}
+	return nil, fmt.Errorf("user %s: %w", id, repositories.ErrNotFound)
+}
+
+func (r *memoryUserRepo) GetByMobile(ctx context.Context, mobile string) (*entities.User, error) {
+	for _, user := range r.users {
+		if user.Mobile == mobile {
+			return &user, nil
+		}
+	}
+	return nil, fmt.Errorf("mobile %s: %w", mobile, repositories.ErrNotFound)
+}
+
+func (r *memoryUserRepo) Activate(ctx context.Context, id uuid.UUID) (bool, error) {
+	user, ok := r.users[id]
+	if !ok || user.StatusID != entities.StatusPending {
+		return false, nil
+	}
+	user.StatusID = entities.StatusActive
+	r.users[id] = user
+	return true, nil
+}
+
+// memoryOtpRepo records the OTPs sent
+type memoryOtpRepo struct {
+	repositories.AuthRepository
+	otps map[uuid.UUID]string
+}
+
+func (r *memoryOtpRepo) SaveOTP(ctx context.Context, userID uuid.UUID, otp string, expiresAt time.Time) error {
+	r.otps[userID] = otp
+	return nil
+}
+
+func TestRegisterAndActivateUser(t *testing.T) {
+	ctx := context.Background()
+	users := &memoryUserRepo{users: make(map[uuid.UUID]entities.User)}
+	otps := &memoryOtpRepo{otps: make(map[uuid.UUID]string)}
+	attempts := &memoryAttemptRepo{attempts: make(map[string]*memoryAttempt)}
+	generateOtp := NewGenerateOtpUseCase(otps, attempts, notifications.NewNoOpSMSService(), config.OTP_EASY)
+	register := NewRegisterUserUseCase(users, generateOtp)
+	activate := NewActivateUserUseCase(users)
+
+	if err := register.Execute(ctx, " +972501234567 ", "  Dana "); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	user, err := users.GetByMobile(ctx, "+972501234567")
+	if err != nil {
+		t.Fatalf("read back: %v", err)
+	}
+	if user.Name != "Dana" || user.Email != "" || user.StatusID != entities.StatusPending {
+		t.Fatalf("registered user = %+v, want a pending user named Dana without email", user)
+	}
+	if otps.otps[user.ID] == "" {
+		t.Fatal("no OTP was sent to the new user")
+	}
+
+	// An unverified sign-up can ask for a new OTP, it is not a new user
+	if err := register.Execute(ctx, "+972501234567", ""); err != nil {
+		t.Fatalf("register again while pending: %v", err)
+	}
+	if len(users.users) != 1 {
+		t.Fatalf("%d users after registering twice, want 1", len(users.users))
+	}
+
+	if err := activate.Execute(ctx, user.ID); err != nil {
+		t.Fatalf("activate: %v", err)
+	}
+	if user, _ := users.GetByID(ctx, user.ID); !user.IsActive() {
+		t.Fatalf("status after activation = %d, want active", user.StatusID)
+	}
+
+	if err := register.Execute(ctx, "+972501234567", ""); err != ErrConflict {
+		t.Fatalf("register an active number = %v, want ErrConflict", err)
+	}
+	if err := register.Execute(ctx, "not-a-number", ""); err != ErrInvalidInput {
+		t.Fatalf("register an invalid number = %v, want ErrInvalidInput", err)
+	}
+}
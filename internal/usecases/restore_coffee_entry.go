@@ -0,0 +1,36 @@
+// file: internal/usecases/restore_coffee_entry.go
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type RestoreCoffeeEntryUseCase struct {
+	coffeeRepo repositories.CoffeeEntryRepository
+}
+
+func NewRestoreCoffeeEntryUseCase(coffeeRepo repositories.CoffeeEntryRepository) *RestoreCoffeeEntryUseCase {
+	return &RestoreCoffeeEntryUseCase{
+		coffeeRepo: coffeeRepo,
+	}
+}
+
+// Execute takes an entry out of the trash. Returns ErrNotFound when it is not in the user's trash
+// (never deleted, or already purged).
+func (uc *RestoreCoffeeEntryUseCase) Execute(ctx context.Context, userID, entryID uuid.UUID) (*entities.CoffeeEntry, error) {
+	entry, err := uc.coffeeRepo.Restore(ctx, entryID, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, ErrInternalError
+	}
+
+	return entry, nil
+}
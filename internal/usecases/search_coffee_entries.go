@@ -0,0 +1,51 @@
+// file: internal/usecases/search_coffee_entries.go
+package usecases
+
+import (
+	"context"
+	"strings"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSearchLimit  = 20
+	maxSearchLimit      = 100
+	maxSearchQueryRunes = 200
+)
+
+type SearchCoffeeEntriesUseCase struct {
+	coffeeRepo repositories.CoffeeEntryRepository
+}
+
+func NewSearchCoffeeEntriesUseCase(coffeeRepo repositories.CoffeeEntryRepository) *SearchCoffeeEntriesUseCase {
+	return &SearchCoffeeEntriesUseCase{
+		coffeeRepo: coffeeRepo,
+	}
+}
+
+// Execute searches the notes of the user's entries. The query supports web search syntax:
+// quoted phrases, "or" and -excluded words.
+func (uc *SearchCoffeeEntriesUseCase) Execute(ctx context.Context, userID uuid.UUID, query string, limit int) ([]entities.EntrySearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || len([]rune(query)) > maxSearchQueryRunes {
+		return nil, ErrInvalidInput
+	}
+
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		return nil, ErrInvalidInput
+	}
+
+	results, err := uc.coffeeRepo.SearchNotes(ctx, userID, query, limit)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	return results, nil
+}
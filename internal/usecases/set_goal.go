@@ -0,0 +1,45 @@
+// file: internal/usecases/set_goal.go
+package usecases
+
+import (
+	"context"
+
+	"coffee-tracker-backend/internal/entities"
+	"coffee-tracker-backend/internal/infrastructure/http/models"
+	"coffee-tracker-backend/internal/infrastructure/utils"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type SetGoalUseCase struct {
+	goalRepo repositories.GoalRepository
+}
+
+func NewSetGoalUseCase(goalRepo repositories.GoalRepository) *SetGoalUseCase {
+	return &SetGoalUseCase{goalRepo: goalRepo}
+}
+
+// Execute creates the goal, or changes the target of the user's existing goal of that type
+func (uc *SetGoalUseCase) Execute(ctx context.Context, userID uuid.UUID, req *models.SetGoalRequest) (*entities.Goal, error) {
+	goalType := entities.GoalType(req.Type)
+	if !goalType.IsValid() || !goalType.IsValidTarget(req.Target) {
+		return nil, ErrInvalidInput
+	}
+
+	now := utils.NowUTC()
+	goal := &entities.Goal{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Type:      goalType,
+		Target:    req.Target,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := uc.goalRepo.Upsert(ctx, goal); err != nil {
+		return nil, ErrInternalError
+	}
+
+	return goal, nil
+}
@@ -3,6 +3,7 @@ package usecases
 
 import (
 	"context"
+	"errors"
 
 	"coffee-tracker-backend/internal/entities"
 	"coffee-tracker-backend/internal/infrastructure/http/models"
@@ -14,11 +15,13 @@ import (
 
 type UpdateCoffeeEntryUseCase struct {
 	coffeeRepo repositories.CoffeeEntryRepository
+	kvRepo     repositories.GenericKVRepository
 }
 
-func NewUpdateCoffeeEntryUseCase(coffeeRepo repositories.CoffeeEntryRepository) *UpdateCoffeeEntryUseCase {
+func NewUpdateCoffeeEntryUseCase(coffeeRepo repositories.CoffeeEntryRepository, kvRepo repositories.GenericKVRepository) *UpdateCoffeeEntryUseCase {
 	return &UpdateCoffeeEntryUseCase{
 		coffeeRepo: coffeeRepo,
+		kvRepo:     kvRepo,
 	}
 }
 
@@ -27,26 +30,48 @@ func (uc *UpdateCoffeeEntryUseCase) Execute(ctx context.Context, userID uuid.UUI
 	// 	return nil, ErrInvalidInput
 	// }
 	
-	// if req.Rating < 1 || req.Rating > 5 {
-	// 	return nil, ErrInvalidInput
-	// }
+	entry, err := updatedCoffeeEntry(ctx, uc.kvRepo, userID, entryID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.coffeeRepo.Update(ctx, entry); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, ErrInternalError
+	}
+
+	return entry, nil
+}
+
+// updatedCoffeeEntry validates an update request and builds the new state of the entry
+func updatedCoffeeEntry(ctx context.Context, kvRepo repositories.GenericKVRepository, userID uuid.UUID, entryID uuid.UUID, req *models.UpdateCoffeeEntryRequest) (*entities.CoffeeEntry, error) {
+	if !isValidRating(req.Rating) {
+		return nil, ErrInvalidInput
+	}
 
-	entry := &entities.CoffeeEntry{
+	caffeine, err := resolveCaffeine(ctx, kvRepo, req.Caffeine, req.CoffeeType, req.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	currency, err := validatePrice(req.Price, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.CoffeeEntry{
 		ID:         	entryID,
 		UserID:     	userID,
 		CoffeeTypeID: 	req.CoffeeType,
 		SizeID:       	req.Size,
-		// Caffeine:   req.Caffeine,
+		Caffeine:   	caffeine,
 		Notes:      	req.Notes,
-		// Price:      req.Price,
-		// Rating:     req.Rating,
+		Price:      	req.Price,
+		Currency:   	currency,
+		Rating:     	req.Rating,
 		Timestamp: 		req.Timestamp,
 		UpdatedAt:  	utils.NowUTC(),
-	}
-
-	if err := uc.coffeeRepo.Update(ctx, entry); err != nil {
-		return nil, ErrInternalError
-	}
-
-	return entry, nil
+	}, nil
 }
@@ -23,5 +23,9 @@ func (uc *UpdateUserSettingUseCase) Execute(ctx context.Context, userID uuid.UUI
 	if !setting.IsValid() {
 		return fmt.Errorf("invalid setting key: %d", setting)
 	}
-	return uc.repo.Patch(ctx, userID, setting, value)
+	normalized, ok := setting.NormalizeValue(value)
+	if !ok {
+		return ErrInvalidInput
+	}
+	return uc.repo.Patch(ctx, userID, setting, normalized)
 }
\ No newline at end of file
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"path"
+	"strings"
 
 	"coffee-tracker-backend/internal/entities"
 	"coffee-tracker-backend/internal/infrastructure/storage"
@@ -50,4 +51,10 @@ func (uc *UploadUserProfileImageUseCase) Execute(ctx context.Context, userID uui
     }
 
     return url, nil
+}
+
+// avatarObjectPath finds the storage path of a profile image from its public URL
+func avatarObjectPath(avatarURL, bucket string) (string, bool) {
+	_, objectPath, found := strings.Cut(avatarURL, "/"+bucket+"/")
+	return objectPath, found && objectPath != ""
 }
\ No newline at end of file
@@ -2,39 +2,158 @@
 package usecases
 
 import (
+	"coffee-tracker-backend/internal/infrastructure/utils"
 	"coffee-tracker-backend/internal/repositories"
 	"context"
+	"crypto/subtle"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// Brute-force protection of OTP verification
+const (
+	otpMaxFailures      = 5                // failures of a user before their OTPs are invalidated and they are locked out
+	otpMaxFailuresPerIP = 20               // failures from a client IP, across users, before it is locked out
+	otpLockoutBase      = 30 * time.Second // the first lockout; it doubles with every further failure
+	otpLockoutMax       = time.Hour
+	OtpFailureWindow    = 24 * time.Hour // failures older than this are forgotten
+)
+
 type ValidateOtpUseCase struct {
-	authRepo repositories.AuthRepository
-	magicOtp  string
+	authRepo    repositories.AuthRepository
+	attemptRepo repositories.OtpAttemptRepository
+	magicOtp    string
+}
+
+func NewValidateOtpUseCase(authRepo repositories.AuthRepository, attemptRepo repositories.OtpAttemptRepository, magicOtp string) *ValidateOtpUseCase {
+	return &ValidateOtpUseCase{authRepo: authRepo, attemptRepo: attemptRepo, magicOtp: magicOtp}
 }
 
-func NewValidateOtpUseCase(authRepo repositories.AuthRepository, magicOtp  string) *ValidateOtpUseCase {
-	return &ValidateOtpUseCase{authRepo: authRepo, magicOtp: magicOtp}
+// Execute checks the OTP of a user, counting failures per user and per client IP.
+// Returns ErrInvalidOTP for a wrong or expired code, and ErrTooManyAttempts with the time to wait
+// while the user or the IP is locked out.
+func (uc *ValidateOtpUseCase) Execute(ctx context.Context, userID uuid.UUID, otp, clientIP string) (time.Duration, error) {
+	keys := otpAttemptKeys(userID, clientIP)
+
+	// Every attempt is counted as a failure before the code is checked, so concurrent guesses
+	// can't all get past the limit; a successful attempt is released afterwards
+	now := utils.NowUTC()
+	failures := make([]int, len(keys))
+	overLimit := false
+	for i, key := range keys {
+		count, lockedUntil, lockEnded, err := uc.attemptRepo.Reserve(ctx, key.name, now.Add(-OtpFailureWindow), now)
+		if err != nil {
+			return 0, ErrInternalError
+		}
+		if lockedUntil.After(now) {
+			// Not checked, so not a failure of the keys reserved already
+			for _, reserved := range keys[:i] {
+				if err := uc.attemptRepo.Release(ctx, reserved.name); err != nil {
+					return 0, ErrInternalError
+				}
+			}
+			return lockedUntil.Sub(now), ErrTooManyAttempts
+		}
+		failures[i] = count
+		// Past the limit, the first attempt after a lockout is checked; the attempts racing it are not
+		overLimit = overLimit || (count > key.maxFailures && !lockEnded)
+	}
+
+	// An attempt refused unchecked only extends the lockout
+	valid := false
+	if !overLimit {
+		var err error
+		if valid, err = uc.check(ctx, userID, otp); err != nil {
+			return 0, ErrInternalError
+		}
+	}
+	if valid {
+		// Clear the user's counter, but only release this attempt of the IP: a success must not
+		// clear the failures of an IP guessing other users
+		if err := uc.attemptRepo.Reset(ctx, keys[0].name); err != nil {
+			return 0, ErrInternalError
+		}
+		for _, key := range keys[1:] {
+			if err := uc.attemptRepo.Release(ctx, key.name); err != nil {
+				return 0, ErrInternalError
+			}
+		}
+		return 0, nil
+	}
+
+	var retryAfter time.Duration
+	for i, key := range keys {
+		if failures[i] < key.maxFailures {
+			continue
+		}
+
+		lockout := otpLockout(failures[i] - key.maxFailures)
+		if err := uc.attemptRepo.Lock(ctx, key.name, now.Add(lockout)); err != nil {
+			return 0, ErrInternalError
+		}
+		retryAfter = max(retryAfter, lockout)
+
+		if key.user && (failures[i]-key.maxFailures)%otpMaxFailures == 0 {
+			// The code may have been narrowed down: a new one has to be requested
+			if err := uc.authRepo.InvalidateUserOTPs(ctx, userID); err != nil {
+				return 0, ErrInternalError
+			}
+		}
+	}
+	if retryAfter > 0 {
+		return retryAfter, ErrTooManyAttempts
+	}
+	return 0, ErrInvalidOTP
 }
 
-func (uc *ValidateOtpUseCase) Execute(ctx context.Context, userID uuid.UUID, otp string) (bool, error) {
-	
-	if otp == uc.magicOtp {
+// check reports whether otp is the magic OTP or a valid code of the user, and uses it up
+func (uc *ValidateOtpUseCase) check(ctx context.Context, userID uuid.UUID, otp string) (bool, error) {
+	// An unset magic OTP must not match an empty code
+	if uc.magicOtp != "" && subtle.ConstantTimeCompare([]byte(otp), []byte(uc.magicOtp)) == 1 {
 		return true, nil
 	}
+	if otp == "" {
+		return false, nil
+	}
+
 	// Check if OTP is valid and not expired
 	valid, err := uc.authRepo.GetValidOTP(ctx, userID, otp)
-	if err != nil {
+	if err != nil || !valid {
 		return false, err
 	}
-	if !valid {
-		return false, ErrInvalidOTP // define this in `usecases/errors.go`
-	}
 	// Invalidate OTP after use
 	if err := uc.authRepo.InvalidateOTP(ctx, userID, otp); err != nil {
-		return true, nil
+		return false, err
 	}
 	return true, nil
+}
+
+type otpAttemptKey struct {
+	name        string
+	maxFailures int
+	user        bool
+}
+
+// otpUserAttemptKey is the counter of the user's failures
+func otpUserAttemptKey(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
 
-	
+// otpAttemptKeys returns the counters of an attempt, the user's first
+func otpAttemptKeys(userID uuid.UUID, clientIP string) []otpAttemptKey {
+	keys := []otpAttemptKey{{name: otpUserAttemptKey(userID), maxFailures: otpMaxFailures, user: true}}
+	if clientIP != "" {
+		keys = append(keys, otpAttemptKey{name: "ip:" + clientIP, maxFailures: otpMaxFailuresPerIP})
+	}
+	return keys
+}
+
+// otpLockout is the lockout after the given number of failures past the limit: 30s, 1m, 2m... up to an hour
+func otpLockout(extraFailures int) time.Duration {
+	lockout := otpLockoutBase
+	for i := 0; i < extraFailures && lockout < otpLockoutMax; i++ {
+		lockout *= 2
+	}
+	return min(lockout, otpLockoutMax)
 }
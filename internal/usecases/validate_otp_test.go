@@ -0,0 +1,158 @@
+// file: internal/usecases/validate_otp_test.go
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"coffee-tracker-backend/internal/infrastructure/config"
+	"coffee-tracker-backend/internal/infrastructure/notifications"
+	"coffee-tracker-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// memoryOtpRepo keeps one code per user; see register_user_test.go for SaveOTP
+func (r *memoryOtpRepo) GetValidOTP(ctx context.Context, userID uuid.UUID, otp string) (bool, error) {
+	return otp != "" && r.otps[userID] == otp, nil
+}
+
+func (r *memoryOtpRepo) InvalidateOTP(ctx context.Context, userID uuid.UUID, otp string) error {
+	delete(r.otps, userID)
+	return nil
+}
+
+func (r *memoryOtpRepo) InvalidateUserOTPs(ctx context.Context, userID uuid.UUID) error {
+	delete(r.otps, userID)
+	return nil
+}
+
+type memoryAttempt struct {
+	failures    int
+	updatedAt   time.Time
+	lockedUntil time.Time
+}
+
+// memoryAttemptRepo follows OtpAttemptRepositoryImpl
+type memoryAttemptRepo struct {
+	repositories.OtpAttemptRepository
+	attempts map[string]*memoryAttempt
+}
+
+func (r *memoryAttemptRepo) Reserve(ctx context.Context, key string, windowStart, now time.Time) (int, time.Time, bool, error) {
+	a, ok := r.attempts[key]
+	if !ok {
+		r.attempts[key] = &memoryAttempt{failures: 1, updatedAt: now}
+		return 1, time.Time{}, false, nil
+	}
+	if a.lockedUntil.After(now) {
+		return a.failures, a.lockedUntil, false, nil
+	}
+	lockEnded := !a.lockedUntil.IsZero()
+	if a.updatedAt.Before(windowStart) {
+		a.failures = 1
+	} else {
+		a.failures++
+	}
+	a.updatedAt, a.lockedUntil = now, time.Time{}
+	return a.failures, time.Time{}, lockEnded, nil
+}
+
+func (r *memoryAttemptRepo) Release(ctx context.Context, key string) error {
+	if a, ok := r.attempts[key]; ok && a.failures > 0 {
+		a.failures--
+	}
+	return nil
+}
+
+func (r *memoryAttemptRepo) Lock(ctx context.Context, key string, until time.Time) error {
+	if a, ok := r.attempts[key]; ok && until.After(a.lockedUntil) {
+		a.lockedUntil = until
+	}
+	return nil
+}
+
+func (r *memoryAttemptRepo) Reset(ctx context.Context, key string) error {
+	delete(r.attempts, key)
+	return nil
+}
+
+// endLockouts moves every lockout to the past, as if the wait was over
+func (r *memoryAttemptRepo) endLockouts() {
+	for _, a := range r.attempts {
+		if !a.lockedUntil.IsZero() {
+			a.lockedUntil = time.Now().Add(-time.Second)
+		}
+	}
+}
+
+func TestValidateOtpAfterLockout(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	otps := &memoryOtpRepo{otps: map[uuid.UUID]string{userID: "1234"}}
+	attempts := &memoryAttemptRepo{attempts: make(map[string]*memoryAttempt)}
+	validate := NewValidateOtpUseCase(otps, attempts, "")
+
+	for i := 1; i < otpMaxFailures; i++ {
+		if _, err := validate.Execute(ctx, userID, "0000", "203.0.113.7"); err != ErrInvalidOTP {
+			t.Fatalf("failure %d = %v, want ErrInvalidOTP", i, err)
+		}
+	}
+	if wait, err := validate.Execute(ctx, userID, "0000", "203.0.113.7"); err != ErrTooManyAttempts || wait != otpLockoutBase {
+		t.Fatalf("failure %d = %v, %v; want ErrTooManyAttempts, %v", otpMaxFailures, wait, err, otpLockoutBase)
+	}
+
+	// The lockout invalidated the code; even a new one is refused until the lockout ends
+	otps.otps[userID] = "5678"
+	if _, err := validate.Execute(ctx, userID, "5678", "203.0.113.7"); err != ErrTooManyAttempts {
+		t.Fatalf("correct code while locked out = %v, want ErrTooManyAttempts", err)
+	}
+
+	attempts.endLockouts()
+	if _, err := validate.Execute(ctx, userID, "5678", "203.0.113.7"); err != nil {
+		t.Fatalf("correct code after the lockout = %v, want it accepted", err)
+	}
+	if _, ok := attempts.attempts[otpUserAttemptKey(userID)]; ok {
+		t.Fatal("the user's counter was not reset by the successful attempt")
+	}
+}
+
+func TestValidateOtpWrongCodeAfterLockoutLocksLonger(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	otps := &memoryOtpRepo{otps: map[uuid.UUID]string{userID: "1234"}}
+	attempts := &memoryAttemptRepo{attempts: make(map[string]*memoryAttempt)}
+	validate := NewValidateOtpUseCase(otps, attempts, "")
+
+	for i := 0; i < otpMaxFailures; i++ {
+		validate.Execute(ctx, userID, "0000", "")
+	}
+	attempts.endLockouts()
+	if wait, err := validate.Execute(ctx, userID, "0000", ""); err != ErrTooManyAttempts || wait != 2*otpLockoutBase {
+		t.Fatalf("wrong code after the lockout = %v, %v; want ErrTooManyAttempts, %v", wait, err, 2*otpLockoutBase)
+	}
+}
+
+func TestNewOtpResetsTheUserAttempts(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	otps := &memoryOtpRepo{otps: make(map[uuid.UUID]string)}
+	attempts := &memoryAttemptRepo{attempts: make(map[string]*memoryAttempt)}
+	generate := NewGenerateOtpUseCase(otps, attempts, notifications.NewNoOpSMSService(), config.OTP_EASY)
+	validate := NewValidateOtpUseCase(otps, attempts, "")
+
+	if err := generate.Execute(ctx, userID, "+972501234567"); err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	for i := 0; i < otpMaxFailures; i++ {
+		validate.Execute(ctx, userID, "wrong", "")
+	}
+
+	if err := generate.Execute(ctx, userID, "+972501234567"); err != nil {
+		t.Fatalf("generate again: %v", err)
+	}
+	if _, err := validate.Execute(ctx, userID, otps.otps[userID], ""); err != nil {
+		t.Fatalf("new code right after a lockout = %v, want it accepted", err)
+	}
+}
@@ -0,0 +1,10 @@
+-- Caffeine tracking: per-type base caffeine, per-size multiplier and per-entry caffeine
+
+ALTER TABLE coffee_types
+    ADD COLUMN IF NOT EXISTS caffeine_mg integer NOT NULL DEFAULT 0;
+
+ALTER TABLE coffee_sizes
+    ADD COLUMN IF NOT EXISTS caffeine_factor numeric(4, 2) NOT NULL DEFAULT 1.00;
+
+ALTER TABLE coffee_entries
+    ADD COLUMN IF NOT EXISTS caffeine_mg integer CHECK (caffeine_mg >= 0);
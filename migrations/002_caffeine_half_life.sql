@@ -0,0 +1,5 @@
+-- Per-user caffeine elimination half-life used by the caffeine curve estimate
+
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS caffeine_half_life_minutes integer NOT NULL DEFAULT 300
+        CHECK (caffeine_half_life_minutes BETWEEN 60 AND 1440);
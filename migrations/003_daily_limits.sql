@@ -0,0 +1,5 @@
+-- Optional per-user daily limits, NULL means "no limit"
+
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS daily_caffeine_limit_mg integer CHECK (daily_caffeine_limit_mg > 0),
+    ADD COLUMN IF NOT EXISTS daily_cup_limit integer CHECK (daily_cup_limit > 0);
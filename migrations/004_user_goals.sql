@@ -0,0 +1,11 @@
+-- Habit goals, at most one per type and user
+
+CREATE TABLE IF NOT EXISTS user_goals (
+    id          uuid PRIMARY KEY,
+    user_id     uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    goal_type   text NOT NULL CHECK (goal_type IN ('max_cups_per_day', 'caffeine_free_days_per_week')),
+    target      integer NOT NULL CHECK (target >= 0),
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    updated_at  timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (user_id, goal_type)
+);
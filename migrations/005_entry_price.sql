@@ -0,0 +1,13 @@
+-- Optional price paid per entry, in an ISO 4217 currency
+
+ALTER TABLE coffee_entries
+    ADD COLUMN IF NOT EXISTS price numeric(10,2) CHECK (price >= 0),
+    ADD COLUMN IF NOT EXISTS currency char(3) CHECK (currency ~ '^[A-Z]{3}$');
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'coffee_entries_price_currency_check') THEN
+        ALTER TABLE coffee_entries
+            ADD CONSTRAINT coffee_entries_price_currency_check CHECK ((price IS NULL) = (currency IS NULL));
+    END IF;
+END $$;
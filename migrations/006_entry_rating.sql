@@ -0,0 +1,4 @@
+-- Optional 1-5 rating per entry
+
+ALTER TABLE coffee_entries
+    ADD COLUMN IF NOT EXISTS rating smallint CHECK (rating BETWEEN 1 AND 5);
@@ -0,0 +1,4 @@
+-- Supports keyset pagination over (timestamp, id) per user
+
+CREATE INDEX IF NOT EXISTS coffee_entries_user_timestamp_id_idx
+    ON coffee_entries (user_id, timestamp, id);
@@ -0,0 +1,9 @@
+-- Full-text search over entry notes. The 'simple' configuration does no stemming, so it
+-- works the same for notes written in any of the supported languages.
+
+ALTER TABLE coffee_entries
+    ADD COLUMN IF NOT EXISTS notes_tsv tsvector
+        GENERATED ALWAYS AS (to_tsvector('simple', COALESCE(notes, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS coffee_entries_notes_tsv_idx
+    ON coffee_entries USING GIN (notes_tsv);
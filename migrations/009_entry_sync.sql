@@ -0,0 +1,27 @@
+-- Change feed for offline sync. Every insert/update of an entry takes a new value from
+-- coffee_entries_version_seq, and deleted entries leave a tombstone with their own version,
+-- so "changes since version N" covers creates, updates and deletes.
+
+CREATE SEQUENCE IF NOT EXISTS coffee_entries_version_seq;
+
+ALTER TABLE coffee_entries
+    ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT nextval('coffee_entries_version_seq'),
+    ADD COLUMN IF NOT EXISTS created_version bigint;
+
+UPDATE coffee_entries SET created_version = version WHERE created_version IS NULL;
+
+ALTER TABLE coffee_entries
+    ALTER COLUMN created_version SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS coffee_entries_user_version_idx
+    ON coffee_entries (user_id, version);
+
+CREATE TABLE IF NOT EXISTS coffee_entry_tombstones (
+    entry_id    uuid PRIMARY KEY,
+    user_id     uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    deleted_at  timestamptz NOT NULL,
+    version     bigint NOT NULL DEFAULT nextval('coffee_entries_version_seq')
+);
+
+CREATE INDEX IF NOT EXISTS coffee_entry_tombstones_user_version_idx
+    ON coffee_entry_tombstones (user_id, version);
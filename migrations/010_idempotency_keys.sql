@@ -0,0 +1,15 @@
+-- Idempotency-Key records: the first response of a mutating request, replayed on retries
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id        uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    key            text NOT NULL,
+    request_hash   text NOT NULL,
+    status_code    integer,             -- NULL while the first request is in progress
+    content_type   text,
+    response_body  bytea,
+    created_at     timestamptz NOT NULL DEFAULT now(),
+    expires_at     timestamptz NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,8 @@
+-- Soft delete: deleted entries stay in a restorable trash until purged after the retention period
+
+ALTER TABLE coffee_entries
+    ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS coffee_entries_deleted_at_idx
+    ON coffee_entries (deleted_at)
+    WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,13 @@
+-- Account data export archives (data portability requests), built in the background
+
+CREATE TABLE IF NOT EXISTS data_exports (
+    id            uuid PRIMARY KEY,
+    user_id       uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status        text NOT NULL CHECK (status IN ('pending', 'ready', 'failed')),
+    object_path   text,                 -- set once the archive is uploaded
+    error         text,                 -- set when the build failed
+    created_at    timestamptz NOT NULL DEFAULT now(),
+    completed_at  timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS data_exports_user_id_created_at_idx ON data_exports (user_id, created_at DESC);
@@ -0,0 +1,9 @@
+-- Self-service account deletion: a deleted account is kept, and restored by logging in again,
+-- until its grace period ends and it is purged with all its data
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deletion_scheduled_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS users_deletion_scheduled_at_idx
+    ON users (deletion_scheduled_at)
+    WHERE deletion_scheduled_at IS NOT NULL;
@@ -0,0 +1,10 @@
+-- Failed OTP verifications, counted per user and per client IP to lock out brute-force guessing
+
+CREATE TABLE IF NOT EXISTS otp_attempts (
+    key           text PRIMARY KEY,          -- "user:<uuid>" or "ip:<address>"
+    failures      integer NOT NULL DEFAULT 0,
+    locked_until  timestamptz,
+    updated_at    timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS otp_attempts_updated_at_idx ON otp_attempts (updated_at);
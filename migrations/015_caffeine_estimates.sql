@@ -0,0 +1,61 @@
+-- Caffeine estimates: 001 added coffee_types.caffeine_mg as NOT NULL DEFAULT 0 without data, so every
+-- estimate was 0 mg. An unknown amount is now NULL (no estimate), and the common types and sizes are
+-- seeded by their English names. Types and sizes with other names keep no estimate / a 1.0 factor
+-- until they are filled in. Existing entries keep their stored amount: a 0 there can't be told apart
+-- from a deliberate decaf override.
+
+ALTER TABLE coffee_types
+    ALTER COLUMN caffeine_mg DROP NOT NULL,
+    ALTER COLUMN caffeine_mg DROP DEFAULT;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'coffee_types_caffeine_mg_check') THEN
+        ALTER TABLE coffee_types
+            ADD CONSTRAINT coffee_types_caffeine_mg_check CHECK (caffeine_mg >= 0);
+    END IF;
+END $$;
+
+UPDATE coffee_types SET caffeine_mg = NULL WHERE caffeine_mg = 0;
+
+-- mg per standard serving
+UPDATE coffee_types ct
+SET caffeine_mg = seed.mg
+FROM coffee_type_translations ctt
+JOIN languages l ON l.id = ctt.language_id AND l.code = 'en'
+JOIN (VALUES
+    ('espresso', 63),
+    ('double espresso', 126),
+    ('ristretto', 50),
+    ('lungo', 80),
+    ('americano', 126),
+    ('long black', 126),
+    ('cappuccino', 63),
+    ('latte', 63),
+    ('flat white', 126),
+    ('macchiato', 63),
+    ('cortado', 63),
+    ('mocha', 95),
+    ('filter coffee', 95),
+    ('drip coffee', 95),
+    ('iced coffee', 95),
+    ('cold brew', 155),
+    ('turkish coffee', 65),
+    ('instant coffee', 62),
+    ('decaf', 3)
+) AS seed (name, mg) ON lower(trim(ctt.name)) = seed.name
+WHERE ctt.coffee_type_id = ct.id AND ct.caffeine_mg IS NULL;
+
+-- multiplier of the standard serving; only sizes still at the 1.00 default
+UPDATE coffee_sizes s
+SET caffeine_factor = seed.factor
+FROM coffee_size_translations st
+JOIN languages l ON l.id = st.language_id AND l.code = 'en'
+JOIN (VALUES
+    ('small', 0.75),
+    ('medium', 1.00),
+    ('large', 1.50),
+    ('extra large', 2.00)
+) AS seed (name, factor) ON lower(trim(st.name)) = seed.name
+WHERE st.coffee_size_id = s.id AND s.caffeine_factor = 1.00;
+
@@ -0,0 +1,8 @@
+-- Single-use tokens (e.g. clear confirmations) already redeemed, kept until they expire
+
+CREATE TABLE IF NOT EXISTS consumed_tokens (
+    jti         text PRIMARY KEY,
+    expires_at  timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS consumed_tokens_expires_at_idx ON consumed_tokens (expires_at);
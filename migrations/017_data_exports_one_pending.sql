@@ -0,0 +1,11 @@
+-- At most one export being built per user, so concurrent requests can't both start one
+
+UPDATE data_exports AS d
+SET status = 'failed', error = 'export timed out', completed_at = now()
+WHERE d.status = 'pending'
+  AND EXISTS (
+    SELECT 1 FROM data_exports AS n
+    WHERE n.user_id = d.user_id AND n.status = 'pending' AND (n.created_at, n.id) > (d.created_at, d.id)
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS data_exports_one_pending_idx ON data_exports (user_id) WHERE status = 'pending';
@@ -0,0 +1,7 @@
+-- The time of a delete, compared by sync, is now the updated_at of the trashed entry, while deleted_at
+-- always holds the server time the entry went to the trash (and is purged after). Entries trashed
+-- before keep their delete time.
+
+UPDATE coffee_entries
+SET updated_at = deleted_at
+WHERE deleted_at IS NOT NULL AND updated_at IS DISTINCT FROM deleted_at;